@@ -0,0 +1,23 @@
+package api
+
+// BatchSolveRequest solves many grids in a single call. It may also be
+// sent as newline delimited JSON, with one SolveRequest per line, in
+// which case TimeoutMs and Parallelism are given as query parameters.
+type BatchSolveRequest struct {
+	// TimeoutMs is the deadline for the whole batch. When zero, the
+	// batch is only limited by the timeouts of its requests.
+	TimeoutMs int `json:"timeout_ms,omitempty"`
+
+	// Parallelism is the maximum number of requests solved
+	// concurrently. It is capped by the server.
+	Parallelism int `json:"parallelism,omitempty"`
+
+	Requests []SolveRequest `json:"requests"`
+}
+
+// BatchSolveResponse contains the result of each request of the batch
+// in the same order as the requests. Requests which failed have their
+// Error set.
+type BatchSolveResponse struct {
+	Results []SolveResponse `json:"results"`
+}
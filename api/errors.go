@@ -0,0 +1,54 @@
+package api
+
+// The codes of the errors returned by the server.
+const (
+	ErrorCodeMalformedRequest  = "malformed_request"
+	ErrorCodeInvalidRequest    = "invalid_request"
+	ErrorCodeRequestTooLarge   = "request_too_large"
+	ErrorCodeInvalidGrid       = "invalid_grid"
+	ErrorCodeNoSolution        = "no_solution"
+	ErrorCodeMultipleSolutions = "multiple_solutions"
+	ErrorCodeGuessRequired     = "guess_required"
+	ErrorCodeTimeout           = "timeout"
+	ErrorCodeCancelled         = "cancelled"
+	ErrorCodeInvalidMove       = "invalid_move"
+	ErrorCodeGridCompleted     = "grid_completed"
+	ErrorCodeGenerationFailed  = "generation_failed"
+	ErrorCodeNotFound          = "not_found"
+	ErrorCodeMethodNotAllowed  = "method_not_allowed"
+	ErrorCodeUnauthorized      = "unauthorized"
+	ErrorCodeForbidden         = "forbidden"
+	ErrorCodeRateLimited       = "rate_limited"
+	ErrorCodeQuotaExceeded     = "quota_exceeded"
+	ErrorCodeOverloaded        = "overloaded"
+	ErrorCodeInternal          = "internal"
+)
+
+// ErrorResponse is the body of every failed request which does not
+// have a more specific response, such as SolveResponse.
+type ErrorResponse struct {
+	Error *Error `json:"error"`
+}
+
+// Error describes why a request failed. Clients should switch on the
+// code, the message is intended for humans.
+type Error struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+
+	// Field is the JSON field of the request which caused the
+	// error, if any.
+	Field string `json:"field,omitempty"`
+
+	// Cell is the cell of the grid which caused the error, if any.
+	Cell *Cell `json:"cell,omitempty"`
+
+	// RequestID identifies the request in the logs of the server.
+	RequestID string `json:"request_id,omitempty"`
+}
+
+// Cell is the location of a cell in a grid, indexed from zero.
+type Cell struct {
+	Row    int `json:"row"`
+	Column int `json:"column"`
+}
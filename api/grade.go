@@ -0,0 +1,38 @@
+package api
+
+// GradeRequest grades the difficulty of a grid with a unique solution.
+type GradeRequest struct {
+	// TimeoutMs is the deadline for grading. Zero means there is no
+	// deadline.
+	TimeoutMs int  `json:"timeout_ms,omitempty"`
+	Grid      Grid `json:"grid"`
+}
+
+// GradeResponse is the difficulty of a grid for a human solver, one of
+// easy, medium, hard or expert, along with the statistics of the
+// search which graded it.
+type GradeResponse struct {
+	Difficulty string      `json:"difficulty"`
+	Stats      *SolveStats `json:"stats,omitempty"`
+}
+
+// GenerateRequest generates a puzzle with a unique solution.
+type GenerateRequest struct {
+	// TimeoutMs is the deadline for generation. Zero means there is
+	// no deadline.
+	TimeoutMs int `json:"timeout_ms,omitempty"`
+
+	// Difficulty is one of easy, medium, hard or expert.
+	Difficulty string `json:"difficulty"`
+
+	// Seed determines the puzzle. Zero means a random seed.
+	Seed int64 `json:"seed,omitempty"`
+}
+
+// GenerateResponse is a generated puzzle, its solution and the seed
+// which generated it.
+type GenerateResponse struct {
+	Puzzle   Grid  `json:"puzzle"`
+	Solution Grid  `json:"solution"`
+	Seed     int64 `json:"seed"`
+}
@@ -0,0 +1,21 @@
+package api
+
+// The statuses of a HealthResponse.
+const (
+	HealthStatusOK       = "ok"
+	HealthStatusDraining = "draining"
+)
+
+// HealthResponse is returned by the liveness and readiness probes. The
+// server is draining once it has started shutting down.
+type HealthResponse struct {
+	Status string `json:"status"`
+}
+
+// BuildInfo describes the build of the server.
+type BuildInfo struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit,omitempty"`
+	BuildTime string `json:"build_time,omitempty"`
+	GoVersion string `json:"go_version"`
+}
@@ -0,0 +1,57 @@
+package api
+
+import (
+	"time"
+)
+
+// JobStatus is the status of an asynchronous solve.
+type JobStatus string
+
+const (
+	JobStatusPending   JobStatus = "pending"
+	JobStatusRunning   JobStatus = "running"
+	JobStatusCompleted JobStatus = "completed"
+	JobStatusFailed    JobStatus = "failed"
+	JobStatusCancelled JobStatus = "cancelled"
+)
+
+// Job is an asynchronous solve created by posting a SolveRequest to
+// /jobs. A timeout_ms of zero means the job runs until it completes or
+// is cancelled.
+type Job struct {
+	ID     string    `json:"id"`
+	Status JobStatus `json:"status"`
+
+	// Result contains the solutions found so far. Its Completed, StoppedBy
+	// and Stats fields are only set once the job has finished, and Error
+	// is set if the job failed.
+	Result SolveResponse `json:"result"`
+
+	// Progress is updated while the job runs, unlike the Stats of its
+	// Result.
+	Progress JobProgress `json:"progress"`
+
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+}
+
+// JobProgress is the progress of a running job.
+type JobProgress struct {
+	// Nodes is the number of nodes of the search tree explored so far,
+	// which is updated every 1000 nodes while the job runs.
+	Nodes int `json:"nodes"`
+
+	// Solutions is the number of solutions found so far.
+	Solutions int `json:"solutions"`
+}
+
+// Finished returns true if the job will not be updated again.
+func (j *Job) Finished() bool {
+	switch j.Status {
+	case JobStatusCompleted, JobStatusFailed, JobStatusCancelled:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,42 @@
+package api
+
+import (
+	"time"
+)
+
+// APIKey describes an API key issued by the /admin/keys endpoint. The
+// key itself is only returned when it is created.
+type APIKey struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+
+	// DailyQuotaMs is the solve time the key may use per UTC day. Zero
+	// means the key is unlimited.
+	DailyQuotaMs int64 `json:"daily_quota_ms,omitempty"`
+
+	// UsedTodayMs is the solve time used by the key since the start of
+	// the UTC day.
+	UsedTodayMs int64 `json:"used_today_ms"`
+
+	CreatedAt time.Time  `json:"created_at"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+}
+
+// CreateAPIKeyRequest issues a new API key.
+type CreateAPIKeyRequest struct {
+	Name         string `json:"name"`
+	DailyQuotaMs int64  `json:"daily_quota_ms,omitempty"`
+}
+
+// CreateAPIKeyResponse contains the new key, which is sent in the
+// X-API-Key header of requests. The server only stores a hash of the
+// key so it cannot be shown again.
+type CreateAPIKeyResponse struct {
+	APIKey
+	Key string `json:"key"`
+}
+
+// APIKeyList is the list of keys issued by the /admin/keys endpoint.
+type APIKeyList struct {
+	Keys []APIKey `json:"keys"`
+}
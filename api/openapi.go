@@ -0,0 +1,13 @@
+package api
+
+import (
+	_ "embed"
+)
+
+// OpenAPI is the OpenAPI 3 document describing the HTTP API. It is
+// generated from the types of this package by running
+//
+//	go test ./internal/openapi -update
+//
+//go:embed openapi.json
+var OpenAPI []byte
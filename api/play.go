@@ -0,0 +1,68 @@
+package api
+
+// The actions a player can send to a play session.
+const (
+	PlayActionStart  = "start"
+	PlayActionPlace  = "place"
+	PlayActionRemove = "remove"
+	PlayActionPencil = "pencil"
+	PlayActionHint   = "hint"
+)
+
+// The names of the events sent by a play session.
+const (
+	PlayEventState = "state"
+	PlayEventHint  = "hint"
+	PlayEventError = "error"
+)
+
+// PlayMessage is a message sent by a player over the /play websocket.
+// A session must be started before any other action is sent, and can
+// be restarted with a new puzzle at any time.
+type PlayMessage struct {
+	Action string `json:"action"`
+
+	// Grid is the puzzle of a start action. When empty a puzzle of
+	// the difficulty is generated instead.
+	Grid       Grid   `json:"grid,omitempty"`
+	Difficulty string `json:"difficulty,omitempty"`
+
+	// Row, Column and Value identify the cell and digit of the place,
+	// remove and pencil actions.
+	Row    int `json:"row"`
+	Column int `json:"column"`
+	Value  int `json:"value,omitempty"`
+}
+
+// PlayEvent is a message sent to the player in reply to each of their
+// messages. The state is sent after every action which changes the
+// grid, a hint after a hint action and an error if the action failed.
+type PlayEvent struct {
+	Event string     `json:"event"`
+	State *PlayState `json:"state,omitempty"`
+	Hint  *Hint      `json:"hint,omitempty"`
+	Error *Error     `json:"error,omitempty"`
+}
+
+// PlayState is the state of a play session.
+type PlayState struct {
+	Puzzle Grid `json:"puzzle"`
+	Grid   Grid `json:"grid"`
+
+	// PencilMarks holds the pencil marks of each cell.
+	PencilMarks [][][]int `json:"pencil_marks"`
+
+	// Conflicts are the filled cells which break the rules.
+	Conflicts []Cell `json:"conflicts"`
+	Completed bool   `json:"completed"`
+}
+
+// Hint is an entry which can be placed in a grid.
+type Hint struct {
+	Cell
+	Value int `json:"value"`
+
+	// Technique is the technique which deduces the entry. It is
+	// empty if the entry could only be found by searching.
+	Technique string `json:"technique,omitempty"`
+}
@@ -5,9 +5,90 @@ type Grid [][]int
 type SolveRequest struct {
 	TimeoutMs int  `json:"timeout_ms,omitempty"`
 	Grid      Grid `json:"grid,omitempty"`
+
+	// Techniques is the ordered list of deduction techniques to
+	// apply. When empty the solver's default techniques are used.
+	Techniques []string `json:"techniques,omitempty"`
+
+	// DisabledTechniques are removed from the list of techniques.
+	DisabledTechniques []string `json:"disabled_techniques,omitempty"`
+
+	// LogicOnly prevents the solver from guessing once the
+	// techniques are exhausted.
+	LogicOnly bool `json:"logic_only,omitempty"`
+
+	// MaxSolutions, MaxNodes and MaxDepth are deterministic budgets
+	// for the search. Zero means there is no limit.
+	MaxSolutions int `json:"max_solutions,omitempty"`
+	MaxNodes     int `json:"max_nodes,omitempty"`
+	MaxDepth     int `json:"max_depth,omitempty"`
+
+	// Stats requests the detailed statistics of the search.
+	Stats bool `json:"stats,omitempty"`
+
+	// NoCache solves the grid even if its result is cached. The header
+	// Cache-Control: no-cache has the same effect.
+	NoCache bool `json:"no_cache,omitempty"`
 }
 
 type SolveResponse struct {
 	Completed bool
 	Solutions []Grid
+
+	// StoppedBy is the limit which stopped the search, if any: one of
+	// timeout, cancelled, max_nodes, max_depth or max_solutions.
+	StoppedBy string      `json:"stopped_by,omitempty"`
+	Stats     *SolveStats `json:"stats,omitempty"`
+
+	// Error is set when the solve failed, in which case Solutions
+	// contains any solutions found before the failure.
+	Error *Error `json:"error,omitempty"`
+
+	// Cached is set if the result was returned from the cache of the
+	// server rather than searched for.
+	Cached bool `json:"cached,omitempty"`
+}
+
+// SolveStats are the statistics of the search. The fields other than
+// Nodes and MaxDepth are only set when the request sets Stats.
+type SolveStats struct {
+	Nodes    int `json:"nodes"`
+	MaxDepth int `json:"max_depth"`
+
+	// Guesses is the number of values tried while branching.
+	Guesses int `json:"guesses,omitempty"`
+
+	// Deductions is the number of times each technique made progress.
+	Deductions map[string]int `json:"deductions,omitempty"`
+
+	// DurationMs is the duration of the search, of which DeductionMs
+	// was spent applying techniques and SearchMs was spent branching.
+	DurationMs  float64 `json:"duration_ms,omitempty"`
+	DeductionMs float64 `json:"deduction_ms,omitempty"`
+	SearchMs    float64 `json:"search_ms,omitempty"`
+}
+
+// The names of the events of a streamed solve response.
+const (
+	EventSolution = "solution"
+	EventDone     = "done"
+	EventError    = "error"
+)
+
+// SolveEvent is a single event of a streamed solve response. Solutions
+// are streamed as they are found, followed by a final done event
+// containing the summary or error event containing the error and the
+// summary, if the search was started.
+type SolveEvent struct {
+	Solution Grid          `json:"solution,omitempty"`
+	Summary  *SolveSummary `json:"summary,omitempty"`
+	Error    *Error        `json:"error,omitempty"`
+}
+
+// SolveSummary summarises a streamed solve response.
+type SolveSummary struct {
+	Completed bool        `json:"completed"`
+	Count     int         `json:"count"`
+	StoppedBy string      `json:"stopped_by,omitempty"`
+	Stats     *SolveStats `json:"stats,omitempty"`
 }
@@ -0,0 +1,336 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             (unknown)
+// source: sudoku/v1/sudoku.proto
+
+package sudokupb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	Sudoku_Solve_FullMethodName       = "/sudoku.v1.Sudoku/Solve"
+	Sudoku_SolveStream_FullMethodName = "/sudoku.v1.Sudoku/SolveStream"
+	Sudoku_Validate_FullMethodName    = "/sudoku.v1.Sudoku/Validate"
+	Sudoku_Hint_FullMethodName        = "/sudoku.v1.Sudoku/Hint"
+	Sudoku_Grade_FullMethodName       = "/sudoku.v1.Sudoku/Grade"
+	Sudoku_Generate_FullMethodName    = "/sudoku.v1.Sudoku/Generate"
+)
+
+// SudokuClient is the client API for Sudoku service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type SudokuClient interface {
+	// Solve returns the solutions of a grid.
+	Solve(ctx context.Context, in *SolveRequest, opts ...grpc.CallOption) (*SolveResponse, error)
+	// SolveStream streams the solutions of a grid as they are found,
+	// followed by a summary of the search.
+	SolveStream(ctx context.Context, in *SolveRequest, opts ...grpc.CallOption) (Sudoku_SolveStreamClient, error)
+	// Validate returns the cells of a grid which break the rules.
+	Validate(ctx context.Context, in *ValidateRequest, opts ...grpc.CallOption) (*ValidateResponse, error)
+	// Hint returns the next entry which can be placed in a grid.
+	Hint(ctx context.Context, in *HintRequest, opts ...grpc.CallOption) (*HintResponse, error)
+	// Grade returns the difficulty of a grid with a unique solution.
+	Grade(ctx context.Context, in *GradeRequest, opts ...grpc.CallOption) (*GradeResponse, error)
+	// Generate returns a puzzle with a unique solution.
+	Generate(ctx context.Context, in *GenerateRequest, opts ...grpc.CallOption) (*GenerateResponse, error)
+}
+
+type sudokuClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewSudokuClient(cc grpc.ClientConnInterface) SudokuClient {
+	return &sudokuClient{cc}
+}
+
+func (c *sudokuClient) Solve(ctx context.Context, in *SolveRequest, opts ...grpc.CallOption) (*SolveResponse, error) {
+	out := new(SolveResponse)
+	err := c.cc.Invoke(ctx, Sudoku_Solve_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sudokuClient) SolveStream(ctx context.Context, in *SolveRequest, opts ...grpc.CallOption) (Sudoku_SolveStreamClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Sudoku_ServiceDesc.Streams[0], Sudoku_SolveStream_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &sudokuSolveStreamClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Sudoku_SolveStreamClient interface {
+	Recv() (*SolveStreamResponse, error)
+	grpc.ClientStream
+}
+
+type sudokuSolveStreamClient struct {
+	grpc.ClientStream
+}
+
+func (x *sudokuSolveStreamClient) Recv() (*SolveStreamResponse, error) {
+	m := new(SolveStreamResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *sudokuClient) Validate(ctx context.Context, in *ValidateRequest, opts ...grpc.CallOption) (*ValidateResponse, error) {
+	out := new(ValidateResponse)
+	err := c.cc.Invoke(ctx, Sudoku_Validate_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sudokuClient) Hint(ctx context.Context, in *HintRequest, opts ...grpc.CallOption) (*HintResponse, error) {
+	out := new(HintResponse)
+	err := c.cc.Invoke(ctx, Sudoku_Hint_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sudokuClient) Grade(ctx context.Context, in *GradeRequest, opts ...grpc.CallOption) (*GradeResponse, error) {
+	out := new(GradeResponse)
+	err := c.cc.Invoke(ctx, Sudoku_Grade_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sudokuClient) Generate(ctx context.Context, in *GenerateRequest, opts ...grpc.CallOption) (*GenerateResponse, error) {
+	out := new(GenerateResponse)
+	err := c.cc.Invoke(ctx, Sudoku_Generate_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// SudokuServer is the server API for Sudoku service.
+// All implementations must embed UnimplementedSudokuServer
+// for forward compatibility
+type SudokuServer interface {
+	// Solve returns the solutions of a grid.
+	Solve(context.Context, *SolveRequest) (*SolveResponse, error)
+	// SolveStream streams the solutions of a grid as they are found,
+	// followed by a summary of the search.
+	SolveStream(*SolveRequest, Sudoku_SolveStreamServer) error
+	// Validate returns the cells of a grid which break the rules.
+	Validate(context.Context, *ValidateRequest) (*ValidateResponse, error)
+	// Hint returns the next entry which can be placed in a grid.
+	Hint(context.Context, *HintRequest) (*HintResponse, error)
+	// Grade returns the difficulty of a grid with a unique solution.
+	Grade(context.Context, *GradeRequest) (*GradeResponse, error)
+	// Generate returns a puzzle with a unique solution.
+	Generate(context.Context, *GenerateRequest) (*GenerateResponse, error)
+	mustEmbedUnimplementedSudokuServer()
+}
+
+// UnimplementedSudokuServer must be embedded to have forward compatible implementations.
+type UnimplementedSudokuServer struct {
+}
+
+func (UnimplementedSudokuServer) Solve(context.Context, *SolveRequest) (*SolveResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Solve not implemented")
+}
+func (UnimplementedSudokuServer) SolveStream(*SolveRequest, Sudoku_SolveStreamServer) error {
+	return status.Errorf(codes.Unimplemented, "method SolveStream not implemented")
+}
+func (UnimplementedSudokuServer) Validate(context.Context, *ValidateRequest) (*ValidateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Validate not implemented")
+}
+func (UnimplementedSudokuServer) Hint(context.Context, *HintRequest) (*HintResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Hint not implemented")
+}
+func (UnimplementedSudokuServer) Grade(context.Context, *GradeRequest) (*GradeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Grade not implemented")
+}
+func (UnimplementedSudokuServer) Generate(context.Context, *GenerateRequest) (*GenerateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Generate not implemented")
+}
+func (UnimplementedSudokuServer) mustEmbedUnimplementedSudokuServer() {}
+
+// UnsafeSudokuServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to SudokuServer will
+// result in compilation errors.
+type UnsafeSudokuServer interface {
+	mustEmbedUnimplementedSudokuServer()
+}
+
+func RegisterSudokuServer(s grpc.ServiceRegistrar, srv SudokuServer) {
+	s.RegisterService(&Sudoku_ServiceDesc, srv)
+}
+
+func _Sudoku_Solve_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SolveRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SudokuServer).Solve(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Sudoku_Solve_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SudokuServer).Solve(ctx, req.(*SolveRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Sudoku_SolveStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SolveRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(SudokuServer).SolveStream(m, &sudokuSolveStreamServer{stream})
+}
+
+type Sudoku_SolveStreamServer interface {
+	Send(*SolveStreamResponse) error
+	grpc.ServerStream
+}
+
+type sudokuSolveStreamServer struct {
+	grpc.ServerStream
+}
+
+func (x *sudokuSolveStreamServer) Send(m *SolveStreamResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Sudoku_Validate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ValidateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SudokuServer).Validate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Sudoku_Validate_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SudokuServer).Validate(ctx, req.(*ValidateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Sudoku_Hint_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HintRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SudokuServer).Hint(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Sudoku_Hint_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SudokuServer).Hint(ctx, req.(*HintRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Sudoku_Grade_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GradeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SudokuServer).Grade(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Sudoku_Grade_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SudokuServer).Grade(ctx, req.(*GradeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Sudoku_Generate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GenerateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SudokuServer).Generate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Sudoku_Generate_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SudokuServer).Generate(ctx, req.(*GenerateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Sudoku_ServiceDesc is the grpc.ServiceDesc for Sudoku service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Sudoku_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "sudoku.v1.Sudoku",
+	HandlerType: (*SudokuServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Solve",
+			Handler:    _Sudoku_Solve_Handler,
+		},
+		{
+			MethodName: "Validate",
+			Handler:    _Sudoku_Validate_Handler,
+		},
+		{
+			MethodName: "Hint",
+			Handler:    _Sudoku_Hint_Handler,
+		},
+		{
+			MethodName: "Grade",
+			Handler:    _Sudoku_Grade_Handler,
+		},
+		{
+			MethodName: "Generate",
+			Handler:    _Sudoku_Generate_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "SolveStream",
+			Handler:       _Sudoku_SolveStream_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "sudoku/v1/sudoku.proto",
+}
@@ -4,61 +4,296 @@ import (
 	"context"
 	"errors"
 	"flag"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
+	"syscall"
 	"time"
 
+	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/reflection"
 
+	"github.com/PeterEFinch/sudoku-solver/api/sudokupb"
+	"github.com/PeterEFinch/sudoku-solver/internal/auth"
+	"github.com/PeterEFinch/sudoku-solver/internal/buildinfo"
+	"github.com/PeterEFinch/sudoku-solver/internal/cache"
+	"github.com/PeterEFinch/sudoku-solver/internal/certs"
+	"github.com/PeterEFinch/sudoku-solver/internal/config"
+	"github.com/PeterEFinch/sudoku-solver/internal/drain"
 	"github.com/PeterEFinch/sudoku-solver/internal/handlers"
+	"github.com/PeterEFinch/sudoku-solver/internal/jobs"
+	"github.com/PeterEFinch/sudoku-solver/internal/metrics"
+	"github.com/PeterEFinch/sudoku-solver/internal/middleware"
+	"github.com/PeterEFinch/sudoku-solver/internal/rpc"
+	"github.com/PeterEFinch/sudoku-solver/internal/tracing"
 )
 
 func main() {
-	address := flag.String("address", ":8080", "the address for the server")
-	flag.Parse()
+	cfg, err := config.Load(os.Args[0], os.Args[1:])
+	if errors.Is(err, flag.ErrHelp) {
+		return
+	}
+	if err != nil {
+		log.Error().Err(err).Msg("failed to load config")
+		os.Exit(2)
+	}
+
+	configureLogging(cfg.Log)
+
+	shutdownTracing := func(context.Context) error { return nil }
+	if cfg.Tracing.OTLPEndpoint != "" {
+		shutdownTracing, err = tracing.Start(context.Background(), cfg.Tracing.OTLPEndpoint, cfg.Tracing.OTLPInsecure)
+		if err != nil {
+			log.Error().Err(err).Str("otlp-endpoint", cfg.Tracing.OTLPEndpoint).Msg("failed to start tracing")
+			os.Exit(1)
+		}
+	}
+
+	jobManager := jobs.NewManager(jobs.NewMemoryStore(), handlers.JobRunner(),
+		jobs.WithTimeouts(cfg.Solve.DefaultTimeout, cfg.Solve.MaxTimeout),
+		jobs.WithMaxSolutions(cfg.Solve.MaxSolutions),
+	)
+	// Requests, including the play sessions of hijacked connections, are
+	// interrupted when the server shuts down, returning their partial
+	// results
+	drainer := drain.New()
+	solveMiddlewares := []middleware.Middleware{middleware.DefaultTimeout(cfg.Solve.DefaultTimeout)}
+	if cfg.Solve.MaxTimeout > 0 {
+		solveMiddlewares = append(solveMiddlewares, middleware.MaxTimeout(cfg.Solve.MaxTimeout))
+	}
+	// Cache hits are returned without waiting for a solve slot, which
+	// only the solves themselves take
+	if cfg.Cache.Enabled {
+		solveMiddlewares = append(solveMiddlewares, cache.New(cfg.Cache.MaxEntries, cfg.Cache.TTL).Middleware)
+	}
+
+	// The limits are shared by the HTTP and gRPC servers, where each
+	// solve of an HTTP request takes a slot of the concurrency limit
+	concurrencyLimiter := middleware.NewConcurrencyLimiter(cfg.Solve.MaxConcurrent, cfg.Solve.MaxQueued)
+
+	readiness := &handlers.Readiness{}
+	mux := handlers.NewServeMux(cfg.Solve.BatchParallelism, jobManager, readiness, solveMiddlewares...)
+	mux.Handle("/metrics", metrics.Handler())
+
+	middlewares := []middleware.Middleware{
+		middleware.RequestID,
+		middleware.AccessLog,
+		middleware.Recover,
+		middleware.Disable(cfg.Endpoints.Disabled()...),
+		drainer.Middleware,
+		middleware.LimitBody(cfg.Limits.MaxBodyBytes),
+		concurrencyLimiter.Share,
+	}
+
+	grpcUnary := []grpc.UnaryServerInterceptor{drainer.UnaryServerInterceptor}
+	grpcStream := []grpc.StreamServerInterceptor{drainer.StreamServerInterceptor}
+	if cfg.Auth.Enabled() {
+		store, err := auth.NewStore(cfg.Auth.APIKeysFile, cfg.Auth.APIKeyHashes)
+		if err != nil {
+			log.Error().Err(err).Str("api-keys-file", cfg.Auth.APIKeysFile).Msg("failed to load api keys")
+			os.Exit(1)
+		}
+
+		if cfg.Auth.AdminKeyHash != "" {
+			if cfg.Auth.APIKeysFile == "" {
+				log.Warn().Msg("api keys created by the admin are lost when the server stops as no api keys file is set")
+			}
+
+			admin := middleware.Chain(handlers.Keys(store), auth.RequireAdmin(cfg.Auth.AdminKeyHash))
+			mux.Handle("/admin/keys", admin)
+			mux.Handle("/admin/keys/", admin)
+		}
+
+		middlewares = append(middlewares, auth.Authenticate(store, "/health", "/livez", "/readyz", "/version", "/metrics", "/openapi.json", "/admin/"))
+		grpcUnary = append(grpcUnary, auth.UnaryServerInterceptor(store, "/grpc.reflection."))
+		grpcStream = append(grpcStream, auth.StreamServerInterceptor(store, "/grpc.reflection."))
+	}
+
+	if cfg.Limits.RateLimit > 0 {
+		rateLimiter := middleware.NewRateLimiter(cfg.Limits.RateLimit, cfg.Limits.RateBurst)
+		middlewares = append(middlewares, rateLimiter.Middleware)
+		grpcUnary = append(grpcUnary, rateLimiter.UnaryServerInterceptor)
+		grpcStream = append(grpcStream, rateLimiter.StreamServerInterceptor)
+	}
+	grpcUnary = append(grpcUnary, concurrencyLimiter.UnaryServerInterceptor)
+	grpcStream = append(grpcStream, concurrencyLimiter.StreamServerInterceptor)
+
+	handler := middleware.Chain(metrics.Instrument(mux), middlewares...)
+	if cfg.H2C {
+		handler = h2c.NewHandler(handler, &http2.Server{IdleTimeout: cfg.Timeouts.Idle})
+	}
 
-	mux := http.NewServeMux()
-	mux.HandleFunc("/health", handlers.Health)
-	mux.HandleFunc("/solve", handlers.Solve)
 	server := &http.Server{
-		Addr:    *address,
-		Handler: mux,
+		Addr:         cfg.Address,
+		Handler:      handler,
+		ReadTimeout:  cfg.Timeouts.Read,
+		WriteTimeout: cfg.Timeouts.Write,
+		IdleTimeout:  cfg.Timeouts.Idle,
+	}
+
+	grpcOptions := []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(grpcUnary...),
+		grpc.ChainStreamInterceptor(grpcStream...),
+	}
+	if cfg.TLS.Enabled() {
+		reloader, err := certs.NewReloader(certs.Options{
+			CertFile:     cfg.TLS.CertFile,
+			KeyFile:      cfg.TLS.KeyFile,
+			ClientCAFile: cfg.TLS.ClientCAFile,
+			ClientAuth:   cfg.TLS.ClientAuth,
+		})
+		if err != nil {
+			log.Error().Err(err).Str("tls-cert-file", cfg.TLS.CertFile).Msg("failed to load certificates")
+			os.Exit(1)
+		}
+
+		server.TLSConfig = reloader.Config()
+		grpcOptions = append(grpcOptions, grpc.Creds(credentials.NewTLS(reloader.Config())))
 	}
 
-	shutdownOnSignal(server, os.Interrupt)
+	grpcServer := grpc.NewServer(grpcOptions...)
+	sudokupb.RegisterSudokuServer(grpcServer, rpc.NewServer(
+		rpc.WithTimeouts(cfg.Solve.DefaultTimeout, cfg.Solve.MaxTimeout),
+		rpc.WithMaxSolutions(cfg.Solve.MaxSolutions),
+	))
+	reflection.Register(grpcServer)
+	if cfg.GRPCAddress != "" {
+		go serveGRPC(grpcServer, cfg.GRPCAddress)
+	}
+
+	stopped := shutdownOnSignal(server, grpcServer, readiness, drainer, jobManager, cfg.Timeouts, os.Interrupt, syscall.SIGTERM)
+
+	log.Info().Interface("build", buildinfo.Get()).Str("address", cfg.Address).Bool("tls", cfg.TLS.Enabled()).Bool("mtls", cfg.TLS.ClientCAFile != "").Bool("h2c", cfg.H2C).Msg("starting server")
+	if cfg.TLS.Enabled() {
+		// The certificates are given by the TLS config
+		err = server.ListenAndServeTLS("", "")
+	} else {
+		err = server.ListenAndServe()
+	}
+	if !errors.Is(err, http.ErrServerClosed) {
+		log.Error().Err(err).Str("address", cfg.Address).Msg("listen and serve failed")
+		os.Exit(1)
+	}
+
+	// Waits for the requests in flight to return
+	<-stopped
+
+	// Exports the spans of the final requests
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := shutdownTracing(ctx); err != nil {
+		log.Warn().Err(err).Msg("failed to flush traces")
+	}
+}
+
+// configureLogging sets the level and format of the global logger.
+func configureLogging(cfg config.Log) {
+	level, _ := zerolog.ParseLevel(cfg.Level)
+	zerolog.SetGlobalLevel(level)
+
+	if cfg.Format == "console" {
+		log.Logger = log.Output(zerolog.ConsoleWriter{Out: os.Stderr})
+	}
+}
+
+// serveGRPC serves gRPC calls on the address, exiting if the server
+// fails.
+func serveGRPC(server *grpc.Server, address string) {
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		log.Error().Err(err).Str("address", address).Msg("failed to listen for gRPC calls")
+		os.Exit(1)
+	}
 
-	log.Info().Str("address", *address).Msg("starting server")
-	if err := server.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
-		log.Error().Err(err).Str("address", *address).Msg("listen and serve failed")
+	log.Info().Str("address", address).Msg("starting gRPC server")
+	if err := server.Serve(listener); err != nil {
+		log.Error().Err(err).Str("address", address).Msg("gRPC serve failed")
 		os.Exit(1)
 	}
 }
 
-// shutdownOnSignal will attempt to gracefully shutdown the server when one of
-// the given signals is sent.
+// shutdownOnSignal will attempt to gracefully shutdown the servers when one of
+// the given signals is sent. The readiness probes fail for the drain delay
+// before the servers stop accepting requests. The requests, including play
+// sessions, and jobs in flight are then interrupted, returning their partial
+// results, and the servers wait up to the shutdown timeout for the requests to
+// finish. The returned channel is
+// closed once the servers have stopped.
 //
 // The waiting for a signal is done in a go routine to ensure that this method
 // is not blocking.
-func shutdownOnSignal(server *http.Server, signals ...os.Signal) {
+func shutdownOnSignal(
+	server *http.Server,
+	grpcServer *grpc.Server,
+	readiness *handlers.Readiness,
+	drainer *drain.Drainer,
+	jobManager *jobs.Manager,
+	timeouts config.Timeouts,
+	signals ...os.Signal,
+) <-chan struct{} {
+	stopped := make(chan struct{})
 	if len(signals) == 0 {
 		log.Warn().Msg("server is not listening to any shutdown signals")
-		return
+		return stopped
 	}
 
 	signalCh := make(chan os.Signal, 1)
 	signal.Notify(signalCh, signals...)
 
 	go func() {
+		defer close(stopped)
+
 		sig := <-signalCh
 		signal.Stop(signalCh)
 		close(signalCh)
-		log.Info().Str("signal", sig.String()).Msg("server shutting down")
+		log.Info().Str("signal", sig.String()).Dur("drain-delay", timeouts.DrainDelay).Msg("server shutting down")
+
+		// Gives load balancers time to notice the server is not ready
+		readiness.Drain()
+		time.Sleep(timeouts.DrainDelay)
+
+		ctx, cancel := context.WithTimeout(context.Background(), timeouts.Shutdown)
+		defer cancel()
+
+		// Stops accepting requests before interrupting the ones in flight,
+		// which are then waited for
+		shutdownErr := make(chan error, 1)
+		go func() { shutdownErr <- server.Shutdown(ctx) }()
+
+		grpcStopped := make(chan struct{})
+		go func() {
+			grpcServer.GracefulStop()
+			close(grpcStopped)
+		}()
 
-		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
-		if err := server.Shutdown(ctx); err != nil {
+		interrupted := drainer.Drain()
+		cancelledJobs := jobManager.Close()
+
+		if err := <-shutdownErr; err != nil {
 			log.Warn().Err(err).Msg("failed to shutdown server")
 		}
-		cancel()
+
+		// The server does not wait for hijacked connections
+		if err := drainer.Wait(ctx); err != nil {
+			log.Warn().Err(err).Msg("failed to wait for play sessions")
+		}
+
+		// Stops the gRPC server forcefully if the calls do not finish in time
+		select {
+		case <-grpcStopped:
+		case <-ctx.Done():
+			log.Warn().Err(ctx.Err()).Msg("failed to gracefully stop gRPC server")
+			grpcServer.Stop()
+		}
+
+		log.Info().Int("interrupted-requests", interrupted).Int("cancelled-jobs", cancelledJobs).Msg("server stopped")
 	}()
+
+	return stopped
 }
@@ -0,0 +1,340 @@
+// Package client is a Go client for the HTTP API of the sudoku solver.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/PeterEFinch/sudoku-solver/api"
+)
+
+// Option configures a Client.
+type Option func(client *Client)
+
+// WithHTTPClient sets the HTTP client used to send requests. The
+// default is http.DefaultClient.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(client *Client) {
+		client.httpClient = httpClient
+	}
+}
+
+// WithRetries sets the maximum number of times a request is retried
+// after a network error or a temporary failure of the server, i.e. a
+// rate limit, a bad gateway or an overloaded server. The default is 3.
+func WithRetries(retries int) Option {
+	return func(client *Client) {
+		client.retries = retries
+	}
+}
+
+// WithBackoff sets the delay before the first retry, which doubles
+// after each retry up to the maximum delay. The default is 100ms up
+// to 5s. A Retry-After header of the server takes precedence.
+func WithBackoff(initial, max time.Duration) Option {
+	return func(client *Client) {
+		client.initialBackoff = initial
+		client.maxBackoff = max
+	}
+}
+
+// WithAPIKey sets the API key sent with every request, which is
+// required if the server has authentication enabled.
+func WithAPIKey(key string) Option {
+	return func(client *Client) {
+		client.apiKey = key
+	}
+}
+
+// Client sends requests to the HTTP API. It is safe for concurrent
+// use.
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+	apiKey     string
+
+	retries        int
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+}
+
+// New returns a client for the server at the base URL, e.g.
+// http://localhost:8080.
+func New(baseURL string, options ...Option) (*Client, error) {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("invalid base url %q: scheme must be http or https", baseURL)
+	}
+
+	client := &Client{
+		baseURL:        strings.TrimSuffix(baseURL, "/"),
+		httpClient:     http.DefaultClient,
+		retries:        3,
+		initialBackoff: 100 * time.Millisecond,
+		maxBackoff:     5 * time.Second,
+	}
+
+	for _, option := range options {
+		option(client)
+	}
+
+	return client, nil
+}
+
+// Solve solves the grid of the request. If the solve fails the
+// response is returned along with an *Error, containing any solutions
+// found before the failure.
+func (c *Client) Solve(ctx context.Context, request *api.SolveRequest) (*api.SolveResponse, error) {
+	response := &api.SolveResponse{}
+	err := c.do(ctx, http.MethodPost, "/solve", request, response)
+	var apiErr *Error
+	if errors.As(err, &apiErr) && response.Error != nil {
+		return response, err
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// SolveBatch solves every request of the batch. The failure of a single
+// request of the batch is reported by the Error of its result.
+func (c *Client) SolveBatch(ctx context.Context, request *api.BatchSolveRequest) (*api.BatchSolveResponse, error) {
+	response := &api.BatchSolveResponse{}
+	err := c.do(ctx, http.MethodPost, "/solve/batch", request, response)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// Grade grades the difficulty of a grid with a unique solution.
+func (c *Client) Grade(ctx context.Context, request *api.GradeRequest) (*api.GradeResponse, error) {
+	response := &api.GradeResponse{}
+	err := c.do(ctx, http.MethodPost, "/grade", request, response)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// Generate generates a puzzle of the requested difficulty.
+func (c *Client) Generate(ctx context.Context, request *api.GenerateRequest) (*api.GenerateResponse, error) {
+	response := &api.GenerateResponse{}
+	err := c.do(ctx, http.MethodPost, "/generate", request, response)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// Health returns an error if the server is not ready to accept work,
+// e.g. as it is shutting down.
+func (c *Client) Health(ctx context.Context) error {
+	return c.do(ctx, http.MethodGet, "/readyz", nil, nil)
+}
+
+// Version returns the build info of the server.
+func (c *Client) Version(ctx context.Context) (*api.BuildInfo, error) {
+	response := &api.BuildInfo{}
+	err := c.do(ctx, http.MethodGet, "/version", nil, response)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// do sends the request, retrying temporary failures, and unmarshals the
+// body of the response into the response. Failed requests return an
+// *Error, and the body is still unmarshalled into the response if it
+// is a JSON object.
+func (c *Client) do(ctx context.Context, method, path string, request, response interface{}) error {
+	var body []byte
+	if request != nil {
+		var err error
+		body, err = json.Marshal(request)
+		if err != nil {
+			return err
+		}
+	}
+
+	backoff := c.initialBackoff
+	for attempt := 0; ; attempt++ {
+		status, header, bs, err := c.send(ctx, method, path, body)
+		if attempt == c.retries || !retryable(status, bs, err) || ctx.Err() != nil {
+			if err != nil {
+				return err
+			}
+
+			return decodeResponse(status, bs, response)
+		}
+
+		delay := backoff
+		if retryAfter, ok := parseRetryAfter(header); ok {
+			delay = retryAfter
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+
+		backoff *= 2
+		if backoff > c.maxBackoff {
+			backoff = c.maxBackoff
+		}
+	}
+}
+
+// send sends a single request and returns the status, header and body
+// of the response.
+func (c *Client) send(ctx context.Context, method, path string, body []byte) (int, http.Header, []byte, error) {
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, bytes.NewReader(body))
+	if err != nil {
+		return 0, nil, nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Accept", "application/json")
+	if c.apiKey != "" {
+		req.Header.Set("X-API-Key", c.apiKey)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return 0, nil, nil, err
+	}
+	defer resp.Body.Close()
+
+	bs, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, nil, nil, err
+	}
+
+	return resp.StatusCode, resp.Header, bs, nil
+}
+
+// retryable returns true if the request failed due to a network error
+// or a temporary failure of the server, given the status and body of
+// its response. Responses with the code of an error which retrying does
+// not fix, e.g. a cancelled solve or an exhausted quota, are final.
+// Responses without a code come from a proxy rather than the server.
+func retryable(status int, bs []byte, err error) bool {
+	if err != nil {
+		return !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+	}
+
+	var response struct {
+		Error *api.Error `json:"error"`
+	}
+	code := ""
+	if json.Unmarshal(bs, &response) == nil && response.Error != nil {
+		code = response.Error.Code
+	}
+
+	switch status {
+	case http.StatusTooManyRequests:
+		return code != api.ErrorCodeQuotaExceeded
+	case http.StatusBadGateway:
+		return true
+	case http.StatusServiceUnavailable:
+		return code == "" || code == api.ErrorCodeOverloaded
+	}
+
+	return false
+}
+
+// parseRetryAfter returns the delay given by the Retry-After header, in
+// either seconds or as an HTTP date, if any.
+func parseRetryAfter(header http.Header) (time.Duration, bool) {
+	value := header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		delay := time.Until(date)
+		if delay < 0 {
+			delay = 0
+		}
+		return delay, true
+	}
+
+	return 0, false
+}
+
+// decodeResponse unmarshals the body into the response, returning an
+// *Error if the status is not successful.
+func decodeResponse(status int, bs []byte, response interface{}) error {
+	if status >= 200 && status < 300 {
+		if response == nil || len(bs) == 0 {
+			return nil
+		}
+
+		return json.Unmarshal(bs, response)
+	}
+
+	// Solve responses contain the error alongside partial results,
+	// every other response is an api.ErrorResponse
+	errResponse := &api.ErrorResponse{}
+	_ = json.Unmarshal(bs, errResponse)
+	if response != nil {
+		_ = json.Unmarshal(bs, response)
+	}
+
+	if errResponse.Error == nil {
+		errResponse.Error = &api.Error{
+			Code:    api.ErrorCodeInternal,
+			Message: strings.TrimSpace(string(bs)),
+		}
+	}
+
+	return &Error{Status: status, Err: errResponse.Error}
+}
+
+// Error is returned for requests which the server failed.
+type Error struct {
+	// Status is the HTTP status of the response.
+	Status int
+	Err    *api.Error
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%d %s: %s", e.Status, e.Err.Code, e.Err.Message)
+}
+
+// Code returns the error code of err if it is an *Error, otherwise it
+// returns an empty string.
+func Code(err error) string {
+	var apiErr *Error
+	if errors.As(err, &apiErr) {
+		return apiErr.Err.Code
+	}
+
+	return ""
+}
@@ -0,0 +1,185 @@
+package client_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/PeterEFinch/sudoku-solver/api"
+	"github.com/PeterEFinch/sudoku-solver/client"
+	"github.com/PeterEFinch/sudoku-solver/client/clienttest"
+)
+
+var difficultGrid = api.Grid{
+	{8, 0, 0, 0, 0, 0, 0, 0, 0},
+	{0, 0, 3, 6, 0, 0, 0, 0, 0},
+	{0, 7, 0, 0, 9, 0, 2, 0, 0},
+	{0, 5, 0, 0, 0, 7, 0, 0, 0},
+	{0, 0, 0, 0, 4, 5, 7, 0, 0},
+	{0, 0, 0, 1, 0, 0, 0, 3, 0},
+	{0, 0, 1, 0, 0, 0, 0, 6, 8},
+	{0, 0, 8, 5, 0, 0, 0, 1, 0},
+	{0, 9, 0, 0, 0, 0, 4, 0, 0},
+}
+
+var invalidGrid = api.Grid{
+	{8, 8, 0, 0, 0, 0, 0, 0, 0},
+	{0, 0, 0, 0, 0, 0, 0, 0, 0},
+	{0, 0, 0, 0, 0, 0, 0, 0, 0},
+	{0, 0, 0, 0, 0, 0, 0, 0, 0},
+	{0, 0, 0, 0, 0, 0, 0, 0, 0},
+	{0, 0, 0, 0, 0, 0, 0, 0, 0},
+	{0, 0, 0, 0, 0, 0, 0, 0, 0},
+	{0, 0, 0, 0, 0, 0, 0, 0, 0},
+	{0, 0, 0, 0, 0, 0, 0, 0, 0},
+}
+
+func TestClient(t *testing.T) {
+	server := clienttest.NewServer()
+	defer server.Close()
+
+	c := server.Client()
+	ctx := context.Background()
+
+	err := c.Health(ctx)
+	if err != nil {
+		t.Fatalf("expected a healthy server, got %v", err)
+	}
+
+	version, err := c.Version(ctx)
+	if err != nil || version.GoVersion == "" {
+		t.Fatalf("expected the build info, got %+v: %v", version, err)
+	}
+
+	solved, err := c.Solve(ctx, &api.SolveRequest{Grid: difficultGrid})
+	if err != nil || len(solved.Solutions) != 1 {
+		t.Fatalf("expected a single solution, got %+v: %v", solved, err)
+	}
+
+	failed, err := c.Solve(ctx, &api.SolveRequest{TimeoutMs: 5000, Grid: invalidGrid})
+	if client.Code(err) != api.ErrorCodeInvalidGrid || failed == nil || failed.Error == nil {
+		t.Fatalf("expected error code %q with a response, got %+v: %v", api.ErrorCodeInvalidGrid, failed, err)
+	}
+
+	batch, err := c.SolveBatch(ctx, &api.BatchSolveRequest{
+		Requests: []api.SolveRequest{{TimeoutMs: 5000, Grid: difficultGrid}, {TimeoutMs: 5000, Grid: invalidGrid}},
+	})
+	if err != nil || len(batch.Results) != 2 || batch.Results[1].Error == nil {
+		t.Fatalf("expected the second request of the batch to fail, got %+v: %v", batch, err)
+	}
+
+	generated, err := c.Generate(ctx, &api.GenerateRequest{Difficulty: "easy", Seed: 1})
+	if err != nil || generated.Seed != 1 {
+		t.Fatalf("expected a puzzle with seed 1, got %+v: %v", generated, err)
+	}
+
+	grade, err := c.Grade(ctx, &api.GradeRequest{Grid: generated.Puzzle})
+	if err != nil || grade.Difficulty != "easy" {
+		t.Fatalf("expected difficulty easy, got %+v: %v", grade, err)
+	}
+
+	_, err = c.Generate(ctx, &api.GenerateRequest{Difficulty: "impossible"})
+	if client.Code(err) != api.ErrorCodeInvalidRequest {
+		t.Fatalf("expected error code %q, got %v", api.ErrorCodeInvalidRequest, err)
+	}
+}
+
+func TestClient_Retries(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   int
+		code     string
+		attempts int32
+	}{
+		{"overloaded", http.StatusServiceUnavailable, api.ErrorCodeOverloaded, 3},
+		{"rate limited", http.StatusTooManyRequests, api.ErrorCodeRateLimited, 3},
+		{"bad gateway", http.StatusBadGateway, "", 3},
+		{"proxy unavailable", http.StatusServiceUnavailable, "", 3},
+		{"cancelled", http.StatusServiceUnavailable, api.ErrorCodeCancelled, 1},
+		{"generation failed", http.StatusServiceUnavailable, api.ErrorCodeGenerationFailed, 1},
+		{"quota exceeded", http.StatusTooManyRequests, api.ErrorCodeQuotaExceeded, 1},
+		{"internal", http.StatusInternalServerError, api.ErrorCodeInternal, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var attempts int32
+			server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+				if atomic.AddInt32(&attempts, 1) < 3 {
+					writeError(rw, tt.status, tt.code)
+					return
+				}
+				rw.WriteHeader(http.StatusOK)
+			}))
+			defer server.Close()
+
+			c, err := client.New(server.URL, client.WithBackoff(time.Millisecond, time.Millisecond))
+			if err != nil {
+				t.Fatalf("failed to create client: %v", err)
+			}
+
+			_ = c.Health(context.Background())
+			if attempts != tt.attempts {
+				t.Fatalf("expected %d attempts, got %d", tt.attempts, attempts)
+			}
+		})
+	}
+
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+		writeError(rw, http.StatusServiceUnavailable, api.ErrorCodeOverloaded)
+	}))
+	defer server.Close()
+
+	c, err := client.New(server.URL, client.WithBackoff(time.Millisecond, time.Millisecond))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	err = c.Health(context.Background())
+	if client.Code(err) != api.ErrorCodeOverloaded || attempts != 4 {
+		t.Fatalf("expected the last failure to be returned after 4 attempts, got %d attempts: %v", attempts, err)
+	}
+}
+
+func TestClient_RetryAfter(t *testing.T) {
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+		if atomic.AddInt32(&attempts, 1) == 1 {
+			rw.Header().Set("Retry-After", "1")
+			writeError(rw, http.StatusTooManyRequests, api.ErrorCodeRateLimited)
+			return
+		}
+		rw.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	c, err := client.New(server.URL, client.WithBackoff(time.Millisecond, time.Millisecond))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	start := time.Now()
+	err = c.Health(context.Background())
+	if elapsed := time.Since(start); err != nil || elapsed < time.Second {
+		t.Fatalf("expected success after waiting for the Retry-After header, got %v after %v", err, elapsed)
+	}
+}
+
+// writeError writes an error response with the status and code, where
+// an empty code writes no body as a proxy would.
+func writeError(rw http.ResponseWriter, status int, code string) {
+	if code == "" {
+		rw.WriteHeader(status)
+		return
+	}
+
+	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(status)
+	_ = json.NewEncoder(rw).Encode(map[string]interface{}{"error": api.Error{Code: code, Message: code}})
+}
@@ -0,0 +1,54 @@
+// Package clienttest runs the HTTP API in-process for integration tests
+// of code using the client package.
+package clienttest
+
+import (
+	"net/http/httptest"
+	"runtime"
+	"time"
+
+	"github.com/PeterEFinch/sudoku-solver/client"
+	"github.com/PeterEFinch/sudoku-solver/internal/handlers"
+	"github.com/PeterEFinch/sudoku-solver/internal/jobs"
+	"github.com/PeterEFinch/sudoku-solver/internal/middleware"
+)
+
+// defaultTimeout is the timeout of solves which do not give a
+// timeout_ms, matching the default of the server.
+const defaultTimeout = 10 * time.Second
+
+// Server is an in-process server of the HTTP API listening on a local
+// address. It must be closed once the test is finished.
+type Server struct {
+	*httptest.Server
+
+	jobManager *jobs.Manager
+}
+
+// NewServer starts and returns a new server.
+func NewServer() *Server {
+	jobManager := jobs.NewManager(jobs.NewMemoryStore(), handlers.JobRunner())
+	return &Server{
+		Server:     httptest.NewServer(handlers.NewServeMux(runtime.NumCPU(), jobManager, &handlers.Readiness{}, middleware.DefaultTimeout(defaultTimeout))),
+		jobManager: jobManager,
+	}
+}
+
+// Client returns a client of the server with the given options.
+func (s *Server) Client(options ...client.Option) *client.Client {
+	options = append([]client.Option{client.WithHTTPClient(s.Server.Client())}, options...)
+	c, err := client.New(s.URL, options...)
+	if err != nil {
+		// The URL of the server is always valid
+		panic(err)
+	}
+
+	return c
+}
+
+// Close shuts down the server, blocking until all outstanding requests
+// have completed, and cancels any running jobs.
+func (s *Server) Close() {
+	s.Server.Close()
+	s.jobManager.Close()
+}
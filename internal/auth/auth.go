@@ -0,0 +1,180 @@
+package auth
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/PeterEFinch/sudoku-solver/api"
+	"github.com/PeterEFinch/sudoku-solver/internal/middleware"
+)
+
+// HeaderAPIKey is the header clients send their API key in. The key can
+// also be sent as a bearer token in the Authorization header.
+const HeaderAPIKey = "X-API-Key"
+
+// clientKey is the context key of the authenticated client.
+type clientKey struct{}
+
+// client is the key authenticating a request and the store it belongs
+// to, which the usage of the key is charged to.
+type client struct {
+	store *Store
+	key   Key
+}
+
+// FromContext returns the key the request of the context was
+// authenticated with, if any.
+func FromContext(ctx context.Context) (Key, bool) {
+	c, ok := ctx.Value(clientKey{}).(*client)
+	if !ok {
+		return Key{}, false
+	}
+
+	return c.key, true
+}
+
+// Authenticate rejects requests without a valid key of the store with
+// 401. The paths starting with one of the exempt prefixes are served
+// without a key. Authenticated requests are rate limited by their key
+// rather than their IP address, while the failed attempts of each IP
+// address are limited by the store, rejecting the requests beyond the
+// limit with 429.
+func Authenticate(store *Store, exempt ...string) middleware.Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			for _, prefix := range exempt {
+				if strings.HasPrefix(req.URL.Path, prefix) {
+					next.ServeHTTP(rw, req)
+					return
+				}
+			}
+
+			// Guesses beyond the limit are rejected before the key is
+			// checked
+			fail, delay, err := store.failures.Check(req.RemoteAddr)
+			if err != nil {
+				middleware.WriteRateLimited(rw, req, delay, err)
+				return
+			}
+
+			secret := keyFromRequest(req)
+			if secret == "" {
+				fail()
+				unauthorized(rw, req, ErrMissingKey)
+				return
+			}
+
+			key, err := store.Authenticate(secret)
+			if err != nil {
+				fail()
+				unauthorized(rw, req, err)
+				return
+			}
+
+			ctx := context.WithValue(req.Context(), clientKey{}, &client{store: store, key: key})
+			ctx = middleware.WithClientID(ctx, key.ID)
+			next.ServeHTTP(rw, req.WithContext(ctx))
+		})
+	}
+}
+
+// RequireAdmin rejects requests which do not send the key hashing to
+// adminHash with 401 if they send no key and 403 otherwise.
+func RequireAdmin(adminHash string) middleware.Middleware {
+	adminHash = strings.ToLower(strings.TrimSpace(adminHash))
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			secret := keyFromRequest(req)
+			if secret == "" {
+				unauthorized(rw, req, ErrMissingKey)
+				return
+			}
+
+			if subtle.ConstantTimeCompare([]byte(Hash(secret)), []byte(adminHash)) != 1 {
+				middleware.WriteError(rw, req, http.StatusForbidden, api.ErrorCodeForbidden, "admin key required")
+				return
+			}
+
+			next.ServeHTTP(rw, req)
+		})
+	}
+}
+
+// Meter reserves the quota of the key of the context for a solve, up
+// to the deadline of the context, failing with ErrQuotaExceeded if the
+// key has none left. The reservation is taken from the quota at once so
+// concurrent solves of a key cannot use more than its quota together.
+// The returned context is cancelled when the reservation runs out, and
+// the returned function must be called with the error of the solve once
+// it is done. It charges the time the solve ran to the key in place of
+// the reservation, and returns the error of the solve, which is
+// ErrQuotaExceeded if the solve was stopped by the quota. Requests
+// without a key are not metered.
+func Meter(ctx context.Context) (context.Context, func(err error) error, error) {
+	c, ok := ctx.Value(clientKey{}).(*client)
+	if !ok {
+		return ctx, func(err error) error { return err }, nil
+	}
+
+	var max time.Duration
+	if deadline, ok := ctx.Deadline(); ok {
+		max = time.Until(deadline)
+	}
+
+	day, reserved, limited := c.store.reserve(c.key, max)
+	if limited && reserved <= 0 {
+		return ctx, nil, fmt.Errorf("%w: daily quota of %dms used", ErrQuotaExceeded, c.key.DailyQuotaMs)
+	}
+
+	// Solves which end by the deadline of the context are not stopped
+	// by the quota
+	start := time.Now()
+	metered, cancel := ctx, context.CancelFunc(func() {})
+	stoppedByQuota := limited && (max <= 0 || reserved < max)
+	if stoppedByQuota {
+		metered, cancel = context.WithTimeout(ctx, reserved)
+	}
+
+	return metered, func(err error) error {
+		cancel()
+		c.store.settle(c.key.ID, day, reserved, time.Since(start))
+
+		if err != nil && stoppedByQuota && errors.Is(metered.Err(), context.DeadlineExceeded) && ctx.Err() == nil {
+			return fmt.Errorf("%w: daily quota of %dms used", ErrQuotaExceeded, c.key.DailyQuotaMs)
+		}
+
+		return err
+	}, nil
+}
+
+// keyFromRequest returns the key sent with the request, or an empty
+// string if there is none.
+func keyFromRequest(req *http.Request) string {
+	if key := req.Header.Get(HeaderAPIKey); key != "" {
+		return key
+	}
+
+	return bearerToken(req.Header.Get("Authorization"))
+}
+
+// bearerToken returns the token of the authorization, or an empty
+// string if it is not a bearer token.
+func bearerToken(authorization string) string {
+	const prefix = "Bearer "
+	if len(authorization) > len(prefix) && strings.EqualFold(authorization[:len(prefix)], prefix) {
+		return strings.TrimSpace(authorization[len(prefix):])
+	}
+
+	return ""
+}
+
+// unauthorized writes a 401 response for the error.
+func unauthorized(rw http.ResponseWriter, req *http.Request, err error) {
+	rw.Header().Set("WWW-Authenticate", `Bearer realm="sudoku-solver"`)
+	middleware.WriteError(rw, req, http.StatusUnauthorized, api.ErrorCodeUnauthorized, err.Error())
+}
@@ -0,0 +1,287 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/PeterEFinch/sudoku-solver/api"
+)
+
+func TestStore_Persisted(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keys.json")
+	store, err := NewStore(path, nil)
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+
+	secret, created, err := store.Create("ci", 1000)
+	if err != nil {
+		t.Fatalf("failed to create key: %v", err)
+	}
+
+	revokedSecret, revoked, err := store.Create("old", 0)
+	if err != nil {
+		t.Fatalf("failed to create key: %v", err)
+	}
+	if _, err = store.Revoke(revoked.ID); err != nil {
+		t.Fatalf("failed to revoke key: %v", err)
+	}
+
+	reloaded, err := NewStore(path, nil)
+	if err != nil {
+		t.Fatalf("failed to reload store: %v", err)
+	}
+
+	key, err := reloaded.Authenticate(secret)
+	if err != nil || key.ID != created.ID || key.DailyQuotaMs != 1000 {
+		t.Fatalf("expected key %s with its quota, got %+v and error %v", created.ID, key, err)
+	}
+
+	if _, err = reloaded.Authenticate(revokedSecret); !errors.Is(err, ErrInvalidKey) {
+		t.Fatalf("expected revoked key to be invalid, got %v", err)
+	}
+
+	if _, err = reloaded.Revoke("unknown"); !errors.Is(err, ErrKeyNotFound) {
+		t.Fatalf("expected %v, got %v", ErrKeyNotFound, err)
+	}
+}
+
+func TestAuthenticate(t *testing.T) {
+	store, err := NewStore("", []string{Hash("static-key")})
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+
+	secret, _, err := store.Create("client", 0)
+	if err != nil {
+		t.Fatalf("failed to create key: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		path   string
+		header string
+		value  string
+		status int
+	}{
+		{"api key header", "/solve", HeaderAPIKey, secret, http.StatusOK},
+		{"bearer token", "/solve", "Authorization", "Bearer " + secret, http.StatusOK},
+		{"environment key", "/solve", HeaderAPIKey, "static-key", http.StatusOK},
+		{"missing key", "/solve", "", "", http.StatusUnauthorized},
+		{"invalid key", "/solve", HeaderAPIKey, "sk_invalid", http.StatusUnauthorized},
+		{"exempt path", "/health", "", "", http.StatusOK},
+	}
+
+	handler := Authenticate(store, "/health")(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(http.StatusOK)
+	}))
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, tt.path, nil)
+			if tt.header != "" {
+				req.Header.Set(tt.header, tt.value)
+			}
+			rw := httptest.NewRecorder()
+			handler.ServeHTTP(rw, req)
+
+			if rw.Code != tt.status {
+				t.Fatalf("expected status %d, got %d", tt.status, rw.Code)
+			}
+
+			if tt.status == http.StatusUnauthorized {
+				response := &api.ErrorResponse{}
+				if err := json.Unmarshal(rw.Body.Bytes(), response); err != nil || response.Error.Code != api.ErrorCodeUnauthorized {
+					t.Fatalf("expected %s error, got %s", api.ErrorCodeUnauthorized, rw.Body)
+				}
+			}
+		})
+	}
+}
+
+func TestAuthenticate_Failures(t *testing.T) {
+	store, err := NewStore("", []string{Hash("key")})
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+
+	handler := Authenticate(store)(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(http.StatusOK)
+	}))
+	serve := func(key, remoteAddr string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/solve", nil)
+		req.Header.Set(HeaderAPIKey, key)
+		req.RemoteAddr = remoteAddr
+		rw := httptest.NewRecorder()
+		handler.ServeHTTP(rw, req)
+		return rw
+	}
+
+	// Only failed attempts count against the limit
+	for i := 0; i < 2*failuresBurst; i++ {
+		if rw := serve("key", "192.0.2.1:1234"); rw.Code != http.StatusOK {
+			t.Fatalf("expected status %d from request %d, got %d", http.StatusOK, i, rw.Code)
+		}
+	}
+	for i := 0; i < failuresBurst; i++ {
+		if rw := serve("guess", "192.0.2.1:1234"); rw.Code != http.StatusUnauthorized {
+			t.Fatalf("expected status %d from guess %d, got %d", http.StatusUnauthorized, i, rw.Code)
+		}
+	}
+
+	rw := serve("key", "192.0.2.1:5678")
+	if rw.Code != http.StatusTooManyRequests || rw.Header().Get("Retry-After") == "" {
+		t.Fatalf("expected status %d with Retry-After, got %d", http.StatusTooManyRequests, rw.Code)
+	}
+	if rw := serve("key", "192.0.2.2:1234"); rw.Code != http.StatusOK {
+		t.Fatalf("expected status %d from another address, got %d", http.StatusOK, rw.Code)
+	}
+}
+
+func TestRequireAdmin(t *testing.T) {
+	handler := RequireAdmin(Hash("admin"))(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(http.StatusOK)
+	}))
+
+	tests := []struct {
+		name   string
+		key    string
+		status int
+	}{
+		{"admin", "admin", http.StatusOK},
+		{"other key", "client", http.StatusForbidden},
+		{"missing key", "", http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/admin/keys", nil)
+			if tt.key != "" {
+				req.Header.Set(HeaderAPIKey, tt.key)
+			}
+			rw := httptest.NewRecorder()
+			handler.ServeHTTP(rw, req)
+
+			if rw.Code != tt.status {
+				t.Fatalf("expected status %d, got %d", tt.status, rw.Code)
+			}
+		})
+	}
+}
+
+func TestMeter(t *testing.T) {
+	store, err := NewStore("", nil)
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+
+	secret, key, err := store.Create("client", 50)
+	if err != nil {
+		t.Fatalf("failed to create key: %v", err)
+	}
+
+	var metered func(*http.Request) error
+	handler := Authenticate(store)(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if err := metered(req); err != nil {
+			rw.WriteHeader(http.StatusTooManyRequests)
+		}
+	}))
+	serve := func() int {
+		req := httptest.NewRequest(http.MethodPost, "/solve", nil)
+		req.Header.Set(HeaderAPIKey, secret)
+		rw := httptest.NewRecorder()
+		handler.ServeHTTP(rw, req)
+		return rw.Code
+	}
+
+	// Uses the whole quota, which stops the work as exceeding it
+	metered = func(req *http.Request) error {
+		ctx, done, err := Meter(req.Context())
+		if err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+		case <-time.After(time.Second):
+			t.Errorf("expected the context to be cancelled once the quota was used")
+		}
+
+		if err := done(ctx.Err()); !errors.Is(err, ErrQuotaExceeded) {
+			t.Errorf("expected %v once the quota was used, got %v", ErrQuotaExceeded, err)
+		}
+		return nil
+	}
+	if status := serve(); status != http.StatusOK {
+		t.Fatalf("expected the first request to be served, got %d", status)
+	}
+
+	if used := store.UsedToday(key.ID); used < 50*time.Millisecond {
+		t.Fatalf("expected at least 50ms to be charged, got %v", used)
+	}
+
+	metered = func(req *http.Request) error {
+		_, _, err := Meter(req.Context())
+		if !errors.Is(err, ErrQuotaExceeded) {
+			t.Errorf("expected %v, got %v", ErrQuotaExceeded, err)
+		}
+		return err
+	}
+	if status := serve(); status != http.StatusTooManyRequests {
+		t.Fatalf("expected the quota to be exceeded, got %d", status)
+	}
+}
+
+func TestMeter_Reservations(t *testing.T) {
+	store, err := NewStore("", nil)
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+
+	_, key, err := store.Create("client", 1000)
+	if err != nil {
+		t.Fatalf("failed to create key: %v", err)
+	}
+	ctx := context.WithValue(context.Background(), clientKey{}, &client{store: store, key: key})
+
+	// Solves with a deadline reserve the quota up to their deadline,
+	// which they are stopped by rather than the quota
+	deadlineCtx, cancel := context.WithTimeout(ctx, 600*time.Millisecond)
+	defer cancel()
+	metered, first, err := Meter(deadlineCtx)
+	if err != nil {
+		t.Fatalf("expected the quota to be reserved, got %v", err)
+	}
+	if metered != deadlineCtx {
+		t.Fatalf("expected the solve to keep its deadline")
+	}
+
+	// A concurrent solve only gets the rest of the quota
+	metered, second, err := Meter(ctx)
+	if err != nil {
+		t.Fatalf("expected the rest of the quota to be reserved, got %v", err)
+	}
+	if deadline, ok := metered.Deadline(); !ok || time.Until(deadline) > 450*time.Millisecond {
+		t.Fatalf("expected the solve to be limited by the rest of the quota, got %v", deadline)
+	}
+
+	if _, _, err := Meter(ctx); !errors.Is(err, ErrQuotaExceeded) {
+		t.Fatalf("expected %v while the quota is reserved, got %v", ErrQuotaExceeded, err)
+	}
+
+	// Settling charges the time used in place of the reservations
+	errSolve := errors.New("solve failed")
+	if err := first(errSolve); err != errSolve {
+		t.Fatalf("expected the error of the solve, got %v", err)
+	}
+	_ = second(nil)
+	if used := store.UsedToday(key.ID); used > 100*time.Millisecond {
+		t.Fatalf("expected the reservations to be released, got %v used", used)
+	}
+}
@@ -0,0 +1,14 @@
+package auth
+
+const (
+	ErrMissingKey    Error = "missing_api_key"
+	ErrInvalidKey    Error = "invalid_api_key"
+	ErrKeyNotFound   Error = "api_key_not_found"
+	ErrQuotaExceeded Error = "quota_exceeded"
+)
+
+type Error string
+
+func (e Error) Error() string {
+	return string(e)
+}
@@ -0,0 +1,92 @@
+package auth
+
+import (
+	"context"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/PeterEFinch/sudoku-solver/internal/middleware"
+)
+
+// UnaryServerInterceptor rejects the unary calls of a gRPC server
+// without a valid key of the store with Unauthenticated, see
+// Authenticate. The methods starting with one of the exempt prefixes,
+// e.g. "/grpc.reflection.", are served without a key.
+func UnaryServerInterceptor(store *Store, exempt ...string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, err := authenticateCall(ctx, store, info.FullMethod, exempt)
+		if err != nil {
+			return nil, err
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor rejects the streaming calls of a gRPC server
+// without a valid key of the store with Unauthenticated, see
+// UnaryServerInterceptor.
+func StreamServerInterceptor(store *Store, exempt ...string) grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := authenticateCall(stream.Context(), store, info.FullMethod, exempt)
+		if err != nil {
+			return err
+		}
+
+		return handler(srv, &authenticatedStream{ServerStream: stream, ctx: ctx})
+	}
+}
+
+// authenticateCall returns a copy of the context of the call with its
+// client, or a status error if the call does not send a valid key.
+// Clients send their key in the x-api-key metadata or as a bearer token
+// in the authorization metadata. Calls of peers failing to authenticate
+// too often are rejected with ResourceExhausted.
+func authenticateCall(ctx context.Context, store *Store, method string, exempt []string) (context.Context, error) {
+	for _, prefix := range exempt {
+		if strings.HasPrefix(method, prefix) {
+			return ctx, nil
+		}
+	}
+
+	fail, delay, err := store.failures.Check(middleware.PeerAddress(ctx))
+	if err != nil {
+		return nil, middleware.RateLimitedError(ctx, delay, err)
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	secret := ""
+	if values := md.Get(strings.ToLower(HeaderAPIKey)); len(values) > 0 {
+		secret = values[0]
+	} else if values := md.Get("authorization"); len(values) > 0 {
+		secret = bearerToken(values[0])
+	}
+
+	if secret == "" {
+		fail()
+		return nil, status.Error(codes.Unauthenticated, ErrMissingKey.Error())
+	}
+
+	key, err := store.Authenticate(secret)
+	if err != nil {
+		fail()
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+
+	ctx = context.WithValue(ctx, clientKey{}, &client{store: store, key: key})
+	return middleware.WithClientID(ctx, key.ID), nil
+}
+
+// authenticatedStream is a server stream with the context of its client.
+type authenticatedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authenticatedStream) Context() context.Context {
+	return s.ctx
+}
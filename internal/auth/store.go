@@ -0,0 +1,317 @@
+// Package auth authenticates clients using API keys and enforces their
+// quotas.
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/PeterEFinch/sudoku-solver/internal/middleware"
+)
+
+// keyPrefix starts every key issued by the store, so keys are easy to
+// recognise, e.g. by secret scanners.
+const keyPrefix = "sk_"
+
+// The rate at which each IP address may fail to authenticate, so keys
+// cannot be guessed by brute force. Clients which fail too often are
+// rejected with 429 until they may try again, even with a valid key.
+const (
+	failuresPerSecond = 0.1
+	failuresBurst     = 10
+)
+
+// Key is an API key as stored at rest. Only the hash of the key is
+// stored, which is enough to authenticate requests.
+type Key struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Hash string `json:"hash"`
+
+	// DailyQuota is the solve time the key may use per UTC day. Zero
+	// means the key is unlimited.
+	DailyQuotaMs int64 `json:"daily_quota_ms,omitempty"`
+
+	CreatedAt time.Time  `json:"created_at"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+}
+
+// Hash returns the hex encoded SHA-256 hash of the key. Keys are random
+// so a fast hash is sufficient.
+func Hash(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// Store holds the API keys and the usage of each key. Keys created or
+// revoked are saved to the file of the store, if any, while usage is
+// only kept in memory. It is safe for concurrent use.
+type Store struct {
+	path     string
+	failures *middleware.RateLimiter
+
+	mu     sync.Mutex
+	keys   map[string]*Key // by id
+	hashes map[string]*Key // by hash
+	usage  map[string]*usage
+}
+
+// usage is the solve time used by a key on a UTC day.
+type usage struct {
+	day  string
+	used time.Duration
+}
+
+// NewStore returns a store of the keys in the file at path, which is
+// created when the first key is issued. The hashes are additional keys
+// which are not saved, e.g. given by an environment variable. Either
+// may be empty.
+func NewStore(path string, hashes []string) (*Store, error) {
+	s := &Store{
+		path:     path,
+		failures: middleware.NewRateLimiter(failuresPerSecond, failuresBurst),
+		keys:     make(map[string]*Key),
+		hashes:   make(map[string]*Key),
+		usage:    make(map[string]*usage),
+	}
+
+	if path != "" {
+		bs, err := os.ReadFile(path)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, err
+		}
+
+		var keys []*Key
+		if len(bs) > 0 {
+			err = json.Unmarshal(bs, &keys)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		for _, key := range keys {
+			s.keys[key.ID] = key
+			s.hashes[key.Hash] = key
+		}
+	}
+
+	for _, hash := range hashes {
+		hash = strings.ToLower(strings.TrimSpace(hash))
+		if hash == "" {
+			continue
+		}
+
+		// Static keys are identified by a prefix of their hash
+		key := &Key{ID: "env-" + shortHash(hash), Name: "environment", Hash: hash}
+		s.hashes[hash] = key
+	}
+
+	return s, nil
+}
+
+// Authenticate returns the key which hashes to the same value as the
+// given key, failing with ErrInvalidKey if there is none or it has been
+// revoked.
+func (s *Store) Authenticate(key string) (Key, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored, ok := s.hashes[Hash(key)]
+	if !ok || stored.RevokedAt != nil {
+		return Key{}, ErrInvalidKey
+	}
+
+	return *stored, nil
+}
+
+// Create issues a new key, returning it along with the stored key.
+func (s *Store) Create(name string, dailyQuotaMs int64) (string, Key, error) {
+	secret := keyPrefix + randomHex(24)
+	key := &Key{
+		ID:           randomHex(8),
+		Name:         name,
+		Hash:         Hash(secret),
+		DailyQuotaMs: dailyQuotaMs,
+		CreatedAt:    time.Now().UTC(),
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.keys[key.ID] = key
+	s.hashes[key.Hash] = key
+	err := s.save()
+	if err != nil {
+		delete(s.keys, key.ID)
+		delete(s.hashes, key.Hash)
+		return "", Key{}, err
+	}
+
+	return secret, *key, nil
+}
+
+// Revoke revokes the key with the given id. Revoking a revoked key has
+// no effect.
+func (s *Store) Revoke(id string) (Key, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key, ok := s.keys[id]
+	if !ok {
+		return Key{}, ErrKeyNotFound
+	}
+
+	if key.RevokedAt == nil {
+		now := time.Now().UTC()
+		key.RevokedAt = &now
+		err := s.save()
+		if err != nil {
+			key.RevokedAt = nil
+			return Key{}, err
+		}
+	}
+
+	return *key, nil
+}
+
+// List returns the keys issued by the store ordered by creation time.
+func (s *Store) List() []Key {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	keys := make([]Key, 0, len(s.keys))
+	for _, key := range s.keys {
+		keys = append(keys, *key)
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].CreatedAt.Before(keys[j].CreatedAt)
+	})
+	return keys
+}
+
+// UsedToday returns the solve time used by the key today.
+func (s *Store) UsedToday(id string) time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.usageOf(id, today()).used
+}
+
+// reserve takes up to max of the solve time the key has left today,
+// or all of it if max is zero, returning the day and time reserved. The
+// reservation counts as used until it is settled. It returns false if
+// the key is unlimited, in which case nothing is reserved.
+func (s *Store) reserve(key Key, max time.Duration) (string, time.Duration, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	day := today()
+	u := s.usageOf(key.ID, day)
+	if key.DailyQuotaMs == 0 {
+		return day, 0, false
+	}
+
+	reserved := time.Duration(key.DailyQuotaMs)*time.Millisecond - u.used
+	if reserved <= 0 {
+		return day, 0, true
+	}
+	if max > 0 && max < reserved {
+		reserved = max
+	}
+
+	u.used += reserved
+	return day, reserved, true
+}
+
+// settle replaces the time reserved for a solve of the key on the day
+// with the solve time used. Solves running past midnight are charged to
+// the day they started.
+func (s *Store) settle(id, day string, reserved, used time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if u, ok := s.usage[id]; ok && u.day == day {
+		u.used += used - reserved
+	}
+}
+
+// usageOf returns the usage of the key on the day, resetting it if the
+// day has changed. The mutex must be held.
+func (s *Store) usageOf(id, day string) *usage {
+	u, ok := s.usage[id]
+	if !ok || u.day != day {
+		u = &usage{day: day}
+		s.usage[id] = u
+	}
+
+	return u
+}
+
+// save writes the keys to the file of the store, replacing it
+// atomically. The mutex must be held.
+func (s *Store) save() error {
+	if s.path == "" {
+		return nil
+	}
+
+	keys := make([]*Key, 0, len(s.keys))
+	for _, key := range s.keys {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].CreatedAt.Before(keys[j].CreatedAt)
+	})
+
+	bs, err := json.MarshalIndent(keys, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	_, err = tmp.Write(bs)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), s.path)
+}
+
+// today returns the current UTC day.
+func today() string {
+	return time.Now().UTC().Format("2006-01-02")
+}
+
+// randomHex returns n random bytes encoded as hex.
+func randomHex(n int) string {
+	bs := make([]byte, n)
+	_, _ = rand.Read(bs)
+	return hex.EncodeToString(bs)
+}
+
+// shortHash returns a prefix of the hash which is long enough to tell
+// keys apart in logs.
+func shortHash(hash string) string {
+	if len(hash) > 8 {
+		return hash[:8]
+	}
+
+	return hash
+}
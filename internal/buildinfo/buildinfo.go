@@ -0,0 +1,47 @@
+// Package buildinfo describes the build of the server. The variables
+// are injected at build time using
+//
+//	go build -ldflags "-X github.com/PeterEFinch/sudoku-solver/internal/buildinfo.Version=v1.2.3 ..."
+package buildinfo
+
+import (
+	"runtime"
+	"runtime/debug"
+
+	"github.com/PeterEFinch/sudoku-solver/api"
+)
+
+var (
+	// Version is the released version of the server.
+	Version = "dev"
+
+	// Commit is the git commit the server was built from. When empty,
+	// the commit recorded by the go command is used, if any.
+	Commit = ""
+
+	// BuildTime is when the server was built, in RFC 3339 format.
+	BuildTime = ""
+)
+
+// Get returns the build info of the server.
+func Get() api.BuildInfo {
+	info := api.BuildInfo{
+		Version:   Version,
+		Commit:    Commit,
+		BuildTime: BuildTime,
+		GoVersion: runtime.Version(),
+	}
+
+	if build, ok := debug.ReadBuildInfo(); ok {
+		for _, setting := range build.Settings {
+			switch {
+			case setting.Key == "vcs.revision" && info.Commit == "":
+				info.Commit = setting.Value
+			case setting.Key == "vcs.time" && info.BuildTime == "":
+				info.BuildTime = setting.Value
+			}
+		}
+	}
+
+	return info
+}
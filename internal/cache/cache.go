@@ -0,0 +1,118 @@
+// Package cache caches the results of solves so that puzzles which are
+// solved repeatedly are only searched once.
+package cache
+
+import (
+	"container/list"
+	"context"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Cache is a least recently used cache with an optional bound on its
+// number of entries and an optional time to live. It is safe for
+// concurrent use.
+type Cache struct {
+	maxEntries int
+	ttl        time.Duration
+	now        func() time.Time
+
+	mu      sync.Mutex
+	order   *list.List // of *entry, most recently used first
+	entries map[string]*list.Element
+}
+
+// entry is a value of the cache.
+type entry struct {
+	key       string
+	value     interface{}
+	expiresAt time.Time
+}
+
+// New returns a cache holding at most maxEntries values, each for at
+// most ttl. Zero means there is no bound or expiry respectively.
+func New(maxEntries int, ttl time.Duration) *Cache {
+	return &Cache{
+		maxEntries: maxEntries,
+		ttl:        ttl,
+		now:        time.Now,
+		order:      list.New(),
+		entries:    make(map[string]*list.Element),
+	}
+}
+
+// Get returns the value of the key, if it is cached and has not
+// expired.
+func (c *Cache) Get(key string) (interface{}, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+
+	e := element.Value.(*entry)
+	if c.ttl > 0 && !c.now().Before(e.expiresAt) {
+		c.remove(element)
+		return nil, false
+	}
+
+	c.order.MoveToFront(element)
+	return e.value, true
+}
+
+// Put caches the value of the key, evicting the least recently used
+// value if the cache is full.
+func (c *Cache) Put(key string, value interface{}) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	expiresAt := c.now().Add(c.ttl)
+	if element, ok := c.entries[key]; ok {
+		e := element.Value.(*entry)
+		e.value, e.expiresAt = value, expiresAt
+		c.order.MoveToFront(element)
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(&entry{key: key, value: value, expiresAt: expiresAt})
+	if c.maxEntries > 0 && c.order.Len() > c.maxEntries {
+		c.remove(c.order.Back())
+	}
+}
+
+// Len returns the number of values in the cache, including any which
+// have expired but have not been evicted yet.
+func (c *Cache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.order.Len()
+}
+
+// remove removes the element from the cache. The mutex must be held.
+func (c *Cache) remove(element *list.Element) {
+	c.order.Remove(element)
+	delete(c.entries, element.Value.(*entry).key)
+}
+
+// cacheKey is the context key of the cache.
+type cacheKey struct{}
+
+// Middleware makes the cache available to the handler, see
+// FromContext.
+func (c *Cache) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		ctx := context.WithValue(req.Context(), cacheKey{}, c)
+		next.ServeHTTP(rw, req.WithContext(ctx))
+	})
+}
+
+// FromContext returns the cache of the request of the context, or nil
+// if caching is disabled.
+func FromContext(ctx context.Context) *Cache {
+	c, _ := ctx.Value(cacheKey{}).(*Cache)
+	return c
+}
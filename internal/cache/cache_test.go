@@ -0,0 +1,82 @@
+package cache
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestCache_Evict(t *testing.T) {
+	c := New(2, 0)
+	c.Put("a", 1)
+	c.Put("b", 2)
+
+	// Uses a so that b is the least recently used
+	if value, ok := c.Get("a"); !ok || value != 1 {
+		t.Fatalf("expected a to be 1, got %v, %t", value, ok)
+	}
+
+	c.Put("c", 3)
+	if _, ok := c.Get("b"); ok {
+		t.Fatalf("expected b to be evicted")
+	}
+
+	for key, expected := range map[string]int{"a": 1, "c": 3} {
+		if value, ok := c.Get(key); !ok || value != expected {
+			t.Fatalf("expected %s to be %d, got %v, %t", key, expected, value, ok)
+		}
+	}
+
+	if c.Len() != 2 {
+		t.Fatalf("expected 2 entries, got %d", c.Len())
+	}
+}
+
+func TestCache_Expire(t *testing.T) {
+	now := time.Unix(0, 0)
+	c := New(0, time.Minute)
+	c.now = func() time.Time { return now }
+
+	c.Put("a", 1)
+	now = now.Add(30 * time.Second)
+	c.Put("b", 2)
+
+	now = now.Add(30 * time.Second)
+	if _, ok := c.Get("a"); ok {
+		t.Fatalf("expected a to have expired")
+	}
+
+	if value, ok := c.Get("b"); !ok || value != 2 {
+		t.Fatalf("expected b to be 2, got %v, %t", value, ok)
+	}
+
+	// Replacing a value renews it
+	c.Put("b", 3)
+	now = now.Add(45 * time.Second)
+	if value, ok := c.Get("b"); !ok || value != 3 {
+		t.Fatalf("expected b to be 3, got %v, %t", value, ok)
+	}
+
+	if c.Len() != 1 {
+		t.Fatalf("expected expired entries to be removed, got %d entries", c.Len())
+	}
+}
+
+func TestCache_Middleware(t *testing.T) {
+	c := New(1, 0)
+	var got *Cache
+	handler := c.Middleware(http.HandlerFunc(func(_ http.ResponseWriter, req *http.Request) {
+		got = FromContext(req.Context())
+	}))
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	if got != c {
+		t.Fatalf("expected the cache in the context of the request")
+	}
+
+	if FromContext(context.Background()) != nil {
+		t.Fatalf("expected no cache without the middleware")
+	}
+}
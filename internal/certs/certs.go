@@ -0,0 +1,198 @@
+// Package certs serves TLS using certificates which are reloaded from
+// disk when they are rotated, so the server does not need restarting.
+package certs
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// The client authentication modes of Options.
+const (
+	ClientAuthOptional = "optional"
+	ClientAuthRequire  = "require"
+)
+
+// Options configures the TLS of a server.
+type Options struct {
+	CertFile string
+	KeyFile  string
+
+	// ClientCAFile contains the certificates of the CAs which sign the
+	// certificates of clients. Clients are not authenticated if empty.
+	ClientCAFile string
+
+	// ClientAuth is ClientAuthRequire to reject clients without a valid
+	// certificate, or ClientAuthOptional to only verify the certificates
+	// which are given.
+	ClientAuth string
+}
+
+// checkInterval is the minimum interval between checking the files for
+// changes, so handshakes do not wait on the disk.
+const checkInterval = time.Second
+
+// Reloader holds the certificate of the server and the CAs of clients,
+// reloading the files whenever they are modified. It is safe for
+// concurrent use.
+type Reloader struct {
+	options Options
+
+	// nextCheck is when the files are next checked for changes, in Unix
+	// nanoseconds. The handshake claiming it checks the files while the
+	// others use the current certificates.
+	nextCheck int64
+
+	mu       sync.RWMutex
+	cert     *tls.Certificate
+	clientCA *x509.CertPool
+
+	// checkMu guards modTimes, which are only used by the check.
+	checkMu  sync.Mutex
+	modTimes map[string]time.Time
+}
+
+// NewReloader returns a reloader of the files of the options, failing
+// if they cannot be loaded.
+func NewReloader(options Options) (*Reloader, error) {
+	r := &Reloader{
+		options:   options,
+		nextCheck: time.Now().Add(checkInterval).UnixNano(),
+		modTimes:  make(map[string]time.Time),
+	}
+
+	if err := r.load(); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// Config returns the TLS configuration of the server. The files are
+// checked for changes at most once per second during handshakes, and
+// the previous certificates are kept if the new ones cannot be loaded,
+// e.g. as only one of the files has been replaced so far.
+func (r *Reloader) Config() *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetCertificate: func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+			cert, _ := r.current()
+			return cert, nil
+		},
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			cert, clientCA := r.current()
+			config := &tls.Config{
+				MinVersion:   tls.VersionTLS12,
+				Certificates: []tls.Certificate{*cert},
+				NextProtos:   []string{"h2", "http/1.1"},
+			}
+
+			if clientCA != nil {
+				config.ClientCAs = clientCA
+				config.ClientAuth = tls.RequireAndVerifyClientCert
+				if r.options.ClientAuth == ClientAuthOptional {
+					config.ClientAuth = tls.VerifyClientCertIfGiven
+				}
+			}
+
+			return config, nil
+		},
+	}
+}
+
+// current returns the current certificate and client CAs, reloading
+// them first if their files have changed since they were last checked.
+func (r *Reloader) current() (*tls.Certificate, *x509.CertPool) {
+	r.check()
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.cert, r.clientCA
+}
+
+// check reloads the files if they have been modified, unless they were
+// checked less than checkInterval ago.
+func (r *Reloader) check() {
+	now := time.Now().UnixNano()
+	next := atomic.LoadInt64(&r.nextCheck)
+	if now < next || !atomic.CompareAndSwapInt64(&r.nextCheck, next, now+int64(checkInterval)) {
+		return
+	}
+
+	r.checkMu.Lock()
+	modified := r.modified()
+	r.checkMu.Unlock()
+	if !modified {
+		return
+	}
+
+	if err := r.load(); err != nil {
+		log.Err(err).Msg("failed to reload certificates, keeping the previous ones")
+	} else {
+		log.Info().Str("cert-file", r.options.CertFile).Msg("reloaded certificates")
+	}
+}
+
+// modified returns true if any of the files has been modified since it
+// was last loaded. The check mutex must be held.
+func (r *Reloader) modified() bool {
+	for path, loaded := range r.modTimes {
+		info, err := os.Stat(path)
+		if err == nil && !info.ModTime().Equal(loaded) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// load loads the files, replacing the current certificates only if
+// every file is valid. Invalid files are not loaded again until they
+// are modified.
+func (r *Reloader) load() error {
+	modTimes := make(map[string]time.Time)
+	for _, path := range []string{r.options.CertFile, r.options.KeyFile, r.options.ClientCAFile} {
+		if path == "" {
+			continue
+		}
+
+		info, err := os.Stat(path)
+		if err != nil {
+			return err
+		}
+		modTimes[path] = info.ModTime()
+	}
+	r.checkMu.Lock()
+	r.modTimes = modTimes
+	r.checkMu.Unlock()
+
+	cert, err := tls.LoadX509KeyPair(r.options.CertFile, r.options.KeyFile)
+	if err != nil {
+		return fmt.Errorf("failed to load certificate: %w", err)
+	}
+
+	var clientCA *x509.CertPool
+	if r.options.ClientCAFile != "" {
+		pem, err := os.ReadFile(r.options.ClientCAFile)
+		if err != nil {
+			return err
+		}
+
+		clientCA = x509.NewCertPool()
+		if !clientCA.AppendCertsFromPEM(pem) {
+			return fmt.Errorf("no certificates found in %s", r.options.ClientCAFile)
+		}
+	}
+
+	r.mu.Lock()
+	r.cert, r.clientCA = &cert, clientCA
+	r.mu.Unlock()
+	return nil
+}
@@ -0,0 +1,211 @@
+package certs
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newCert returns a PEM encoded certificate and key signed by the
+// parent, or self-signed if the parent is nil.
+func newCert(t *testing.T, name string, parent *tls.Certificate) ([]byte, []byte, tls.Certificate) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: name},
+		DNSNames:              []string{"localhost"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  parent == nil,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+
+	signer, signerKey := template, interface{}(key)
+	if parent != nil {
+		signer, signerKey = parent.Leaf, parent.PrivateKey
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, signer, &key.PublicKey, signerKey)
+	if err != nil {
+		t.Fatalf("failed to create certificate: %v", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("failed to marshal key: %v", err)
+	}
+
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		t.Fatalf("failed to parse certificate: %v", err)
+	}
+	cert.Leaf, _ = x509.ParseCertificate(der)
+
+	return certPEM, keyPEM, cert
+}
+
+// writeFile writes the file with a modification time after every
+// previous write.
+func writeFile(t *testing.T, path string, bs []byte, modTime time.Time) {
+	t.Helper()
+
+	if err := os.WriteFile(path, bs, 0o600); err != nil {
+		t.Fatalf("failed to write %s: %v", path, err)
+	}
+	if err := os.Chtimes(path, modTime, modTime); err != nil {
+		t.Fatalf("failed to set modification time of %s: %v", path, err)
+	}
+}
+
+func TestReloader_Rotation(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key")
+
+	certPEM, keyPEM, _ := newCert(t, "first", nil)
+	writeFile(t, certFile, certPEM, time.Now())
+	writeFile(t, keyFile, keyPEM, time.Now())
+
+	reloader, err := NewReloader(Options{CertFile: certFile, KeyFile: keyFile})
+	if err != nil {
+		t.Fatalf("failed to create reloader: %v", err)
+	}
+
+	commonName := func(cert *tls.Certificate) string {
+		leaf, err := x509.ParseCertificate(cert.Certificate[0])
+		if err != nil {
+			t.Fatalf("failed to parse certificate: %v", err)
+		}
+		return leaf.Subject.CommonName
+	}
+
+	// Checks the files on the next handshake, as if checkInterval has
+	// passed
+	subject := func() string {
+		atomic.StoreInt64(&reloader.nextCheck, 0)
+		cert, _ := reloader.current()
+		return commonName(cert)
+	}
+
+	if name := subject(); name != "first" {
+		t.Fatalf("expected the first certificate, got %q", name)
+	}
+
+	// Keeps the first certificate while the key does not match
+	certPEM, keyPEM, _ = newCert(t, "second", nil)
+	writeFile(t, certFile, certPEM, time.Now().Add(time.Minute))
+	if name := subject(); name != "first" {
+		t.Fatalf("expected the first certificate to be kept, got %q", name)
+	}
+
+	writeFile(t, keyFile, keyPEM, time.Now().Add(time.Minute))
+	if name := subject(); name != "second" {
+		t.Fatalf("expected the rotated certificate, got %q", name)
+	}
+
+	// Does not check the files again within checkInterval
+	certPEM, keyPEM, _ = newCert(t, "third", nil)
+	writeFile(t, certFile, certPEM, time.Now().Add(2*time.Minute))
+	writeFile(t, keyFile, keyPEM, time.Now().Add(2*time.Minute))
+	if cert, _ := reloader.current(); commonName(cert) != "second" {
+		t.Fatalf("expected the certificate to be kept until the files are checked, got %q", commonName(cert))
+	}
+	if name := subject(); name != "third" {
+		t.Fatalf("expected the rotated certificate, got %q", name)
+	}
+}
+
+func TestReloader_ClientAuth(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile, caFile := filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key"), filepath.Join(dir, "ca.crt")
+
+	caPEM, _, ca := newCert(t, "ca", nil)
+	certPEM, keyPEM, _ := newCert(t, "server", &ca)
+	_, _, client := newCert(t, "client", &ca)
+	_, _, stranger := newCert(t, "stranger", nil)
+	writeFile(t, certFile, certPEM, time.Now())
+	writeFile(t, keyFile, keyPEM, time.Now())
+	writeFile(t, caFile, caPEM, time.Now())
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.Leaf)
+
+	tests := []struct {
+		name       string
+		clientAuth string
+		cert       *tls.Certificate
+		ok         bool
+	}{
+		{"required", ClientAuthRequire, &client, true},
+		{"required without certificate", ClientAuthRequire, nil, false},
+		{"required with unknown certificate", ClientAuthRequire, &stranger, false},
+		{"optional without certificate", ClientAuthOptional, nil, true},
+		{"optional with unknown certificate", ClientAuthOptional, &stranger, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reloader, err := NewReloader(Options{CertFile: certFile, KeyFile: keyFile, ClientCAFile: caFile, ClientAuth: tt.clientAuth})
+			if err != nil {
+				t.Fatalf("failed to create reloader: %v", err)
+			}
+
+			listener, err := tls.Listen("tcp", "127.0.0.1:0", reloader.Config())
+			if err != nil {
+				t.Fatalf("failed to listen: %v", err)
+			}
+			defer listener.Close()
+
+			serverErr := make(chan error, 1)
+			go func() {
+				conn, err := listener.Accept()
+				if err != nil {
+					serverErr <- err
+					return
+				}
+				defer conn.Close()
+				serverErr <- conn.(*tls.Conn).Handshake()
+			}()
+
+			// Sends the certificate even if it is not signed by the CA
+			config := &tls.Config{RootCAs: roots, ServerName: "localhost"}
+			config.GetClientCertificate = func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+				if tt.cert == nil {
+					return &tls.Certificate{}, nil
+				}
+				return tt.cert, nil
+			}
+			conn, err := tls.Dial("tcp", listener.Addr().String(), config)
+			if err == nil {
+				// The client completes its handshake before the server
+				// verifies its certificate
+				_ = conn.SetReadDeadline(time.Now().Add(time.Second))
+				_, _ = conn.Read(make([]byte, 1))
+				conn.Close()
+			}
+
+			err = <-serverErr
+			if (err == nil) != tt.ok {
+				t.Fatalf("expected handshake success %v, got %v", tt.ok, err)
+			}
+		})
+	}
+}
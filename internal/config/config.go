@@ -0,0 +1,435 @@
+// Package config loads the configuration of the server from a YAML
+// file, environment variables and command line flags, in increasing
+// order of precedence.
+package config
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// envPrefix starts the name of every environment variable of the
+// configuration, e.g. SUDOKU_SOLVE_MAX_TIMEOUT sets solve.max_timeout.
+const envPrefix = "SUDOKU_"
+
+// Config is the configuration of the server.
+type Config struct {
+	Address     string `yaml:"address"`
+	GRPCAddress string `yaml:"grpc_address"`
+
+	// H2C serves HTTP/2 without TLS, e.g. for internal traffic.
+	H2C bool `yaml:"h2c"`
+
+	TLS       TLS       `yaml:"tls"`
+	Timeouts  Timeouts  `yaml:"timeouts"`
+	Solve     Solve     `yaml:"solve"`
+	Limits    Limits    `yaml:"limits"`
+	Cache     Cache     `yaml:"cache"`
+	Auth      Auth      `yaml:"auth"`
+	Log       Log       `yaml:"log"`
+	Tracing   Tracing   `yaml:"tracing"`
+	Endpoints Endpoints `yaml:"endpoints"`
+}
+
+// TLS configures serving HTTPS and gRPC over TLS. TLS is disabled
+// unless both files are set. The files are reloaded when they change.
+type TLS struct {
+	CertFile string `yaml:"cert_file"`
+	KeyFile  string `yaml:"key_file"`
+
+	// ClientCAFile enables mutual TLS, authenticating clients by
+	// certificates signed by its CAs.
+	ClientCAFile string `yaml:"client_ca_file"`
+
+	// ClientAuth is require to reject clients without a certificate,
+	// or optional to only verify the certificates which are given.
+	ClientAuth string `yaml:"client_auth"`
+}
+
+// Enabled returns true if TLS is configured.
+func (t TLS) Enabled() bool {
+	return t.CertFile != ""
+}
+
+// Timeouts configures the timeouts of the HTTP server, where zero means
+// no timeout.
+type Timeouts struct {
+	Read     time.Duration `yaml:"read"`
+	Write    time.Duration `yaml:"write"`
+	Idle     time.Duration `yaml:"idle"`
+	Shutdown time.Duration `yaml:"shutdown"`
+
+	// DrainDelay is how long the readiness probes fail before the
+	// server stops accepting requests when shutting down.
+	DrainDelay time.Duration `yaml:"drain_delay"`
+}
+
+// Solve configures the solves of the server.
+type Solve struct {
+	// DefaultTimeout is the timeout of solves without a timeout_ms.
+	DefaultTimeout time.Duration `yaml:"default_timeout"`
+
+	// MaxTimeout caps the timeout_ms of solves, disabled if zero.
+	MaxTimeout time.Duration `yaml:"max_timeout"`
+
+	MaxConcurrent    int `yaml:"max_concurrent"`
+	MaxQueued        int `yaml:"max_queued"`
+	BatchParallelism int `yaml:"batch_parallelism"`
+
+	// MaxSolutions caps the solutions kept by solves whose solutions
+	// are collected rather than streamed, i.e. jobs and unary gRPC
+	// solves.
+	MaxSolutions int `yaml:"max_solutions"`
+}
+
+// Limits configures the limits protecting the server from heavy
+// clients.
+type Limits struct {
+	MaxBodyBytes int64 `yaml:"max_body_bytes"`
+
+	// RateLimit is the requests per second allowed per client,
+	// disabled if zero.
+	RateLimit float64 `yaml:"rate_limit"`
+	RateBurst int     `yaml:"rate_burst"`
+}
+
+// Cache configures the cache of solve results, see package cache.
+type Cache struct {
+	Enabled bool `yaml:"enabled"`
+
+	// MaxEntries bounds the number of results cached, which must be
+	// positive as each result may hold many solutions.
+	MaxEntries int `yaml:"max_entries"`
+
+	// TTL is how long results are cached for, forever if zero.
+	TTL time.Duration `yaml:"ttl"`
+}
+
+// Auth configures the API keys of the server, see package auth.
+type Auth struct {
+	APIKeysFile string `yaml:"api_keys_file"`
+
+	// APIKeyHashes are the hashes of static keys. The environment
+	// variable is a comma separated list.
+	APIKeyHashes []string `yaml:"api_key_hashes" env:"SUDOKU_API_KEYS"`
+
+	AdminKeyHash string `yaml:"admin_key_hash" env:"SUDOKU_ADMIN_KEY_HASH"`
+}
+
+// Enabled returns true if any source of keys is configured.
+func (a Auth) Enabled() bool {
+	return a.APIKeysFile != "" || len(a.APIKeyHashes) > 0 || a.AdminKeyHash != ""
+}
+
+// Log configures the log output.
+type Log struct {
+	// Level is one of debug, info, warn or error.
+	Level string `yaml:"level"`
+
+	// Format is json, or console for human readable output.
+	Format string `yaml:"format"`
+}
+
+// Tracing configures exporting traces, see package tracing.
+type Tracing struct {
+	// OTLPEndpoint is the OTLP gRPC endpoint, disabled if empty.
+	OTLPEndpoint string `yaml:"otlp_endpoint"`
+	OTLPInsecure bool   `yaml:"otlp_insecure"`
+}
+
+// Endpoints enables the optional endpoints of the server.
+type Endpoints struct {
+	Batch    bool `yaml:"batch"`
+	Grade    bool `yaml:"grade"`
+	Generate bool `yaml:"generate"`
+	Jobs     bool `yaml:"jobs"`
+	Play     bool `yaml:"play"`
+	Metrics  bool `yaml:"metrics"`
+	OpenAPI  bool `yaml:"openapi"`
+}
+
+// Disabled returns the paths of the disabled endpoints.
+func (e Endpoints) Disabled() []string {
+	var paths []string
+	for _, endpoint := range []struct {
+		enabled bool
+		path    string
+	}{
+		{e.Batch, "/solve/batch"},
+		{e.Grade, "/grade"},
+		{e.Generate, "/generate"},
+		{e.Jobs, "/jobs"},
+		{e.Play, "/play"},
+		{e.Metrics, "/metrics"},
+		{e.OpenAPI, "/openapi.json"},
+	} {
+		if !endpoint.enabled {
+			paths = append(paths, endpoint.path)
+		}
+	}
+
+	return paths
+}
+
+// Default returns the default configuration.
+func Default() Config {
+	return Config{
+		Address:     ":8080",
+		GRPCAddress: ":9090",
+		TLS: TLS{
+			ClientAuth: "require",
+		},
+		Timeouts: Timeouts{
+			Read:     30 * time.Second,
+			Idle:     2 * time.Minute,
+			Shutdown: 15 * time.Second,
+		},
+		Solve: Solve{
+			DefaultTimeout:   10 * time.Second,
+			MaxTimeout:       30 * time.Second,
+			MaxConcurrent:    runtime.NumCPU(),
+			MaxQueued:        4 * runtime.NumCPU(),
+			BatchParallelism: runtime.NumCPU(),
+			MaxSolutions:     1000,
+		},
+		Limits: Limits{
+			MaxBodyBytes: 1 << 20,
+			RateBurst:    20,
+		},
+		Cache: Cache{
+			Enabled:    true,
+			MaxEntries: 10000,
+			TTL:        time.Hour,
+		},
+		Log: Log{
+			Level:  "info",
+			Format: "json",
+		},
+		Endpoints: Endpoints{
+			Batch:    true,
+			Grade:    true,
+			Generate: true,
+			Jobs:     true,
+			Play:     true,
+			Metrics:  true,
+			OpenAPI:  true,
+		},
+	}
+}
+
+// Load returns the configuration given by the command line arguments.
+// The defaults are overridden by the YAML file given by the -config
+// flag or the SUDOKU_CONFIG environment variable, then by environment
+// variables and finally by the flags which are set. The configuration
+// is validated before it is returned.
+func Load(name string, args []string) (Config, error) {
+	// Finds the file before the flags can be applied on top of it. Bad
+	// flags are reported when they are parsed again
+	scratch := Default()
+	fs := newFlagSet(name, &scratch, io.Discard)
+	path := fs.String("config", os.Getenv(envPrefix+"CONFIG"), "")
+	_ = fs.Parse(args)
+
+	config := Default()
+	if *path != "" {
+		bs, err := os.ReadFile(*path)
+		if err != nil {
+			return Config{}, fmt.Errorf("failed to read config: %w", err)
+		}
+
+		decoder := yaml.NewDecoder(bytes.NewReader(bs))
+		decoder.KnownFields(true)
+		if err := decoder.Decode(&config); err != nil && !errors.Is(err, io.EOF) {
+			return Config{}, fmt.Errorf("failed to parse config %s: %w", *path, err)
+		}
+	}
+
+	if err := applyEnv(reflect.ValueOf(&config).Elem(), envPrefix, os.LookupEnv); err != nil {
+		return Config{}, err
+	}
+
+	fs = newFlagSet(name, &config, os.Stderr)
+	fs.String("config", *path, "the YAML configuration file, also set by SUDOKU_CONFIG")
+	if err := fs.Parse(args); err != nil {
+		return Config{}, err
+	}
+
+	return config, config.Validate()
+}
+
+// newFlagSet returns the flags of the configuration, whose defaults
+// are the current values of the configuration.
+func newFlagSet(name string, c *Config, output io.Writer) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(output)
+
+	fs.StringVar(&c.Address, "address", c.Address, "the address for the server")
+	fs.StringVar(&c.GRPCAddress, "grpc-address", c.GRPCAddress, "the address for the gRPC server, disabled if empty")
+	fs.StringVar(&c.TLS.CertFile, "tls-cert-file", c.TLS.CertFile, "the certificate file for serving HTTPS")
+	fs.StringVar(&c.TLS.KeyFile, "tls-key-file", c.TLS.KeyFile, "the private key file for serving HTTPS")
+	fs.StringVar(&c.TLS.ClientCAFile, "tls-client-ca-file", c.TLS.ClientCAFile, "the CA certificates of clients, enables mutual TLS if set")
+	fs.StringVar(&c.TLS.ClientAuth, "tls-client-auth", c.TLS.ClientAuth, "whether client certificates are required or optional with mutual TLS")
+	fs.BoolVar(&c.H2C, "h2c", c.H2C, "serve HTTP/2 without TLS")
+	fs.DurationVar(&c.Timeouts.Read, "read-timeout", c.Timeouts.Read, "the maximum duration for reading a request, disabled if zero")
+	fs.DurationVar(&c.Timeouts.Write, "write-timeout", c.Timeouts.Write, "the maximum duration for writing a response, disabled if zero")
+	fs.DurationVar(&c.Timeouts.Idle, "idle-timeout", c.Timeouts.Idle, "the maximum duration a keep-alive connection is idle, disabled if zero")
+	fs.DurationVar(&c.Timeouts.Shutdown, "shutdown-timeout", c.Timeouts.Shutdown, "the maximum duration to wait for requests to finish when shutting down")
+	fs.DurationVar(&c.Timeouts.DrainDelay, "drain-delay", c.Timeouts.DrainDelay, "how long the readiness probes fail before the server stops accepting requests when shutting down")
+	fs.DurationVar(&c.Solve.DefaultTimeout, "default-timeout", c.Solve.DefaultTimeout, "the timeout of solve requests without a timeout_ms")
+	fs.DurationVar(&c.Solve.MaxTimeout, "max-timeout", c.Solve.MaxTimeout, "the maximum duration of a solve request, including time spent queued, disabled if zero")
+	fs.IntVar(&c.Solve.MaxConcurrent, "max-concurrent-solves", c.Solve.MaxConcurrent, "the maximum number of solves running concurrently, including those of batches, jobs and play sessions")
+	fs.IntVar(&c.Solve.MaxQueued, "max-queued-solves", c.Solve.MaxQueued, "the maximum number of solves waiting for their turn")
+	fs.IntVar(&c.Solve.BatchParallelism, "batch-parallelism", c.Solve.BatchParallelism, "the maximum number of requests of a batch solved concurrently")
+	fs.IntVar(&c.Solve.MaxSolutions, "max-solutions", c.Solve.MaxSolutions, "the maximum number of solutions kept by jobs and unary gRPC solves")
+	fs.Int64Var(&c.Limits.MaxBodyBytes, "max-body-bytes", c.Limits.MaxBodyBytes, "the maximum size of the body of a request")
+	fs.Float64Var(&c.Limits.RateLimit, "rate-limit", c.Limits.RateLimit, "the average number of requests per second allowed per client, disabled if zero")
+	fs.IntVar(&c.Limits.RateBurst, "rate-burst", c.Limits.RateBurst, "the number of requests a client can make in a burst above the rate limit")
+	fs.BoolVar(&c.Cache.Enabled, "cache", c.Cache.Enabled, "cache the results of solves")
+	fs.IntVar(&c.Cache.MaxEntries, "cache-max-entries", c.Cache.MaxEntries, "the maximum number of solve results cached")
+	fs.DurationVar(&c.Cache.TTL, "cache-ttl", c.Cache.TTL, "how long solve results are cached for, forever if zero")
+	fs.StringVar(&c.Auth.APIKeysFile, "api-keys-file", c.Auth.APIKeysFile, "the file the API keys are stored in, enables authentication if set")
+	fs.StringVar(&c.Auth.AdminKeyHash, "admin-key-hash", c.Auth.AdminKeyHash, "the SHA-256 hash of the admin key, enables /admin/keys if set")
+	fs.StringVar(&c.Log.Level, "log-level", c.Log.Level, "the minimum level logged: debug, info, warn or error")
+	fs.StringVar(&c.Log.Format, "log-format", c.Log.Format, "the format of the logs: json or console")
+	fs.StringVar(&c.Tracing.OTLPEndpoint, "otlp-endpoint", c.Tracing.OTLPEndpoint, "the OTLP gRPC endpoint traces are exported to, disabled if empty")
+	fs.BoolVar(&c.Tracing.OTLPInsecure, "otlp-insecure", c.Tracing.OTLPInsecure, "export traces without TLS")
+	return fs
+}
+
+// applyEnv sets the fields of the struct from the environment variables
+// named after the prefix and the yaml tags of the fields, unless the
+// field has an env tag.
+func applyEnv(v reflect.Value, prefix string, lookup func(string) (string, bool)) error {
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		name := prefix + strings.ToUpper(field.Tag.Get("yaml"))
+		if field.Type.Kind() == reflect.Struct {
+			if err := applyEnv(v.Field(i), name+"_", lookup); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if env := field.Tag.Get("env"); env != "" {
+			name = env
+		}
+		value, ok := lookup(name)
+		if !ok {
+			continue
+		}
+
+		if err := setField(v.Field(i), value); err != nil {
+			return fmt.Errorf("invalid %s %q: %w", name, value, err)
+		}
+	}
+
+	return nil
+}
+
+// setField parses the value into the field.
+func setField(field reflect.Value, value string) error {
+	switch field.Interface().(type) {
+	case string:
+		field.SetString(value)
+	case []string:
+		var values []string
+		for _, v := range strings.Split(value, ",") {
+			if v = strings.TrimSpace(v); v != "" {
+				values = append(values, v)
+			}
+		}
+		field.Set(reflect.ValueOf(values))
+	case bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		field.SetBool(b)
+	case time.Duration:
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return err
+		}
+		field.SetInt(int64(d))
+	case int, int64:
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return err
+		}
+		field.SetInt(n)
+	case float64:
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return err
+		}
+		field.SetFloat(f)
+	default:
+		return fmt.Errorf("unsupported type %s", field.Type())
+	}
+
+	return nil
+}
+
+// Validate returns an error listing every invalid setting.
+func (c Config) Validate() error {
+	var problems []string
+	check := func(ok bool, format string, args ...interface{}) {
+		if !ok {
+			problems = append(problems, fmt.Sprintf(format, args...))
+		}
+	}
+
+	check(c.Address != "", "address must be set")
+	check((c.TLS.CertFile == "") == (c.TLS.KeyFile == ""), "tls.cert_file and tls.key_file must be set together")
+	check(c.TLS.ClientCAFile == "" || c.TLS.Enabled(), "tls.client_ca_file requires tls.cert_file and tls.key_file")
+	check(oneOf(c.TLS.ClientAuth, "require", "optional"), "tls.client_auth must be require or optional, got %q", c.TLS.ClientAuth)
+	check(!c.H2C || !c.TLS.Enabled(), "h2c cannot be combined with tls, which serves HTTP/2 already")
+	check(c.Timeouts.Read >= 0, "timeouts.read must not be negative, got %v", c.Timeouts.Read)
+	check(c.Timeouts.Write >= 0, "timeouts.write must not be negative, got %v", c.Timeouts.Write)
+	check(c.Timeouts.Idle >= 0, "timeouts.idle must not be negative, got %v", c.Timeouts.Idle)
+	check(c.Timeouts.Shutdown > 0, "timeouts.shutdown must be positive, got %v", c.Timeouts.Shutdown)
+	check(c.Timeouts.DrainDelay >= 0, "timeouts.drain_delay must not be negative, got %v", c.Timeouts.DrainDelay)
+	check(c.Solve.DefaultTimeout > 0, "solve.default_timeout must be positive, got %v", c.Solve.DefaultTimeout)
+	check(c.Solve.MaxTimeout >= 0, "solve.max_timeout must not be negative, got %v", c.Solve.MaxTimeout)
+	check(c.Solve.MaxTimeout == 0 || c.Solve.DefaultTimeout <= c.Solve.MaxTimeout,
+		"solve.default_timeout %v must not exceed solve.max_timeout %v", c.Solve.DefaultTimeout, c.Solve.MaxTimeout)
+	check(c.Solve.MaxConcurrent > 0, "solve.max_concurrent must be positive, got %d", c.Solve.MaxConcurrent)
+	check(c.Solve.MaxQueued >= 0, "solve.max_queued must not be negative, got %d", c.Solve.MaxQueued)
+	check(c.Solve.BatchParallelism > 0, "solve.batch_parallelism must be positive, got %d", c.Solve.BatchParallelism)
+	check(c.Solve.MaxSolutions > 0, "solve.max_solutions must be positive, got %d", c.Solve.MaxSolutions)
+	check(c.Limits.MaxBodyBytes > 0, "limits.max_body_bytes must be positive, got %d", c.Limits.MaxBodyBytes)
+	check(c.Limits.RateLimit >= 0, "limits.rate_limit must not be negative, got %g", c.Limits.RateLimit)
+	check(c.Limits.RateLimit == 0 || c.Limits.RateBurst > 0, "limits.rate_burst must be positive, got %d", c.Limits.RateBurst)
+	check(!c.Cache.Enabled || c.Cache.MaxEntries > 0, "cache.max_entries must be positive, got %d", c.Cache.MaxEntries)
+	check(c.Cache.TTL >= 0, "cache.ttl must not be negative, got %v", c.Cache.TTL)
+	check(oneOf(c.Log.Level, "debug", "info", "warn", "error"), "log.level must be debug, info, warn or error, got %q", c.Log.Level)
+	check(oneOf(c.Log.Format, "json", "console"), "log.format must be json or console, got %q", c.Log.Format)
+
+	if len(problems) > 0 {
+		return fmt.Errorf("invalid config: %s", strings.Join(problems, "; "))
+	}
+
+	return nil
+}
+
+// oneOf returns true if the value is one of the allowed values.
+func oneOf(value string, allowed ...string) bool {
+	for _, a := range allowed {
+		if value == a {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,93 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	err := os.WriteFile(path, []byte(`
+address: ":8443"
+solve:
+  default_timeout: 5s
+  max_concurrent: 2
+log:
+  level: debug
+endpoints:
+  play: false
+`), 0o600)
+	if err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	t.Setenv("SUDOKU_SOLVE_MAX_CONCURRENT", "3")
+	t.Setenv("SUDOKU_API_KEYS", "a, b")
+
+	config, err := Load("server", []string{"-config", path, "-log-level", "warn"})
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		got      interface{}
+		expected interface{}
+	}{
+		{"file", config.Address, ":8443"},
+		{"file duration", config.Solve.DefaultTimeout, 5 * time.Second},
+		{"default", config.Solve.MaxTimeout, 30 * time.Second},
+		{"env over file", config.Solve.MaxConcurrent, 3},
+		{"env list", strings.Join(config.Auth.APIKeyHashes, ","), "a,b"},
+		{"flag over file", config.Log.Level, "warn"},
+		{"disabled endpoints", strings.Join(config.Endpoints.Disabled(), ","), "/play"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.got != tt.expected {
+				t.Fatalf("expected %v, got %v", tt.expected, tt.got)
+			}
+		})
+	}
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte("adress: \":8080\"\n"), 0o600); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		args    []string
+		env     string
+		message string
+	}{
+		{"unknown field", []string{"-config", path}, "", "field adress not found"},
+		{"missing file", []string{"-config", path + ".missing"}, "", "failed to read config"},
+		{"invalid env", nil, "soon", "invalid SUDOKU_TIMEOUTS_SHUTDOWN"},
+		{"unknown flag", []string{"-unknown"}, "", "flag provided but not defined"},
+		{"invalid setting", []string{"-max-concurrent-solves", "0", "-log-format", "xml"}, "", "solve.max_concurrent must be positive, got 0; log.format must be json or console"},
+		{"timeouts", []string{"-default-timeout", "1m"}, "", "solve.default_timeout 1m0s must not exceed solve.max_timeout 30s"},
+		{"unbounded cache", []string{"-cache-max-entries", "0"}, "", "cache.max_entries must be positive, got 0"},
+		{"client ca without tls", []string{"-tls-client-ca-file", "ca.crt"}, "", "tls.client_ca_file requires tls.cert_file and tls.key_file"},
+		{"h2c with tls", []string{"-h2c", "-tls-cert-file", "tls.crt", "-tls-key-file", "tls.key"}, "", "h2c cannot be combined with tls"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.env != "" {
+				t.Setenv("SUDOKU_TIMEOUTS_SHUTDOWN", tt.env)
+			}
+
+			_, err := Load("server", tt.args)
+			if err == nil || !strings.Contains(err.Error(), tt.message) {
+				t.Fatalf("expected error containing %q, got %v", tt.message, err)
+			}
+		})
+	}
+}
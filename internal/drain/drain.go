@@ -0,0 +1,118 @@
+// Package drain interrupts the solves in flight when the server shuts
+// down, so they return their partial results rather than delaying the
+// shutdown or being killed abruptly.
+package drain
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"google.golang.org/grpc"
+)
+
+// Drainer tracks the requests in flight and cancels their contexts once
+// the server is draining. It is safe for concurrent use.
+type Drainer struct {
+	draining chan struct{}
+	once     sync.Once
+	inFlight int64
+}
+
+// New returns a drainer which is not draining.
+func New() *Drainer {
+	return &Drainer{draining: make(chan struct{})}
+}
+
+// Drain cancels the contexts of the requests in flight, and of any
+// request which starts afterwards, returning the number of requests it
+// interrupted. Only the first call interrupts requests.
+func (d *Drainer) Drain() int {
+	interrupted := 0
+	d.once.Do(func() {
+		interrupted = int(atomic.LoadInt64(&d.inFlight))
+		close(d.draining)
+	})
+
+	return interrupted
+}
+
+// Wait waits until the requests in flight have finished, including
+// those of hijacked connections, e.g. websockets, which the HTTP server
+// does not wait for when it shuts down. It returns the error of the
+// context if it is done first.
+func (d *Drainer) Wait(ctx context.Context) error {
+	ticker := time.NewTicker(10 * time.Millisecond)
+	defer ticker.Stop()
+
+	for atomic.LoadInt64(&d.inFlight) > 0 {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+
+	return nil
+}
+
+// Track returns a copy of the context which is cancelled when the
+// drainer drains. The returned function must be called once the
+// request finishes.
+func (d *Drainer) Track(ctx context.Context) (context.Context, func()) {
+	atomic.AddInt64(&d.inFlight, 1)
+	ctx, cancel := context.WithCancel(ctx)
+
+	done := make(chan struct{})
+	go func() {
+		select {
+		case <-d.draining:
+			cancel()
+		case <-done:
+		}
+	}()
+
+	return ctx, func() {
+		close(done)
+		cancel()
+		atomic.AddInt64(&d.inFlight, -1)
+	}
+}
+
+// Middleware tracks the requests of the handler.
+func (d *Drainer) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		ctx, done := d.Track(req.Context())
+		defer done()
+
+		next.ServeHTTP(rw, req.WithContext(ctx))
+	})
+}
+
+// UnaryServerInterceptor tracks the unary calls of a gRPC server.
+func (d *Drainer) UnaryServerInterceptor(ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	ctx, done := d.Track(ctx)
+	defer done()
+
+	return handler(ctx, req)
+}
+
+// StreamServerInterceptor tracks the streaming calls of a gRPC server.
+func (d *Drainer) StreamServerInterceptor(srv interface{}, stream grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, done := d.Track(stream.Context())
+	defer done()
+
+	return handler(srv, &trackedStream{ServerStream: stream, ctx: ctx})
+}
+
+// trackedStream is a server stream with the context of the drainer.
+type trackedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *trackedStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,60 @@
+package drain
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestDrainer(t *testing.T) {
+	drainer := New()
+
+	started := make(chan struct{})
+	interrupted := make(chan bool, 1)
+	handler := drainer.Middleware(http.HandlerFunc(func(_ http.ResponseWriter, req *http.Request) {
+		close(started)
+		select {
+		case <-req.Context().Done():
+			interrupted <- true
+		case <-time.After(5 * time.Second):
+			interrupted <- false
+		}
+	}))
+
+	go handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/solve", nil))
+	<-started
+
+	finished, done := drainer.Track(context.Background())
+	done()
+	if finished.Err() == nil {
+		t.Fatalf("expected the context of a finished request to be cancelled")
+	}
+
+	if count := drainer.Drain(); count != 1 {
+		t.Fatalf("expected 1 request to be interrupted, got %d", count)
+	}
+
+	if !<-interrupted {
+		t.Fatalf("expected the request in flight to be interrupted")
+	}
+
+	waitCtx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := drainer.Wait(waitCtx); err != nil {
+		t.Fatalf("expected the interrupted request to finish, got %v", err)
+	}
+
+	ctx, done := drainer.Track(context.Background())
+	defer done()
+	select {
+	case <-ctx.Done():
+	case <-time.After(time.Second):
+		t.Fatalf("expected requests started while draining to be interrupted")
+	}
+
+	if count := drainer.Drain(); count != 0 {
+		t.Fatalf("expected draining again to interrupt nothing, got %d", count)
+	}
+}
@@ -0,0 +1,169 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/PeterEFinch/sudoku-solver/api"
+	"github.com/PeterEFinch/sudoku-solver/internal/metrics"
+)
+
+// SolveBatch returns a handler for requests solving many sudokus. The
+// requests of a batch are solved concurrently by at most
+// maxParallelism goroutines.
+func SolveBatch(maxParallelism int) http.HandlerFunc {
+	return func(rw http.ResponseWriter, req *http.Request) {
+		if !allowMethods(rw, req, http.MethodPost) {
+			return
+		}
+
+		batch, err := readBatchRequest(req)
+		if err != nil {
+			log.Err(err).Msg("failed to read batch request")
+			writeBodyError(rw, req, http.StatusBadRequest, api.ErrorCodeMalformedRequest, err)
+			return
+		}
+
+		err = validateBatchRequest(batch)
+		if err != nil {
+			log.Err(err).Msg("bad request")
+			writeError(rw, req, http.StatusBadRequest, api.ErrorCodeInvalidRequest, err)
+			return
+		}
+
+		parallelism := maxParallelism
+		if batch.Parallelism > 0 && batch.Parallelism < maxParallelism {
+			parallelism = batch.Parallelism
+		}
+
+		ctx, cancel := withTimeoutMs(req.Context(), batch.TimeoutMs)
+		defer cancel()
+
+		response := &api.BatchSolveResponse{
+			Results: solveBatch(ctx, req, batch.Requests, parallelism),
+		}
+		writeJSON(rw, http.StatusOK, response)
+	}
+}
+
+// solveBatch solves the requests using at most parallelism goroutines,
+// returning the results in the order of the requests.
+func solveBatch(ctx context.Context, req *http.Request, requests []api.SolveRequest, parallelism int) []api.SolveResponse {
+	results := make([]api.SolveResponse, len(requests))
+	workers := make(chan struct{}, parallelism)
+	wg := sync.WaitGroup{}
+	for i := range requests {
+		workers <- struct{}{}
+		wg.Add(1)
+		go func(i int) {
+			defer func() {
+				<-workers
+				wg.Done()
+			}()
+			results[i] = solveItem(ctx, req, &requests[i])
+		}(i)
+	}
+	wg.Wait()
+
+	return results
+}
+
+// solveItem validates and solves a single request of a batch. The
+// request is limited by its own timeout, if given, as well as the
+// deadline of the batch.
+func solveItem(ctx context.Context, req *http.Request, request *api.SolveRequest) api.SolveResponse {
+	err := validateSolveRequest(request)
+	if err != nil {
+		metrics.SolveRejected()
+		return api.SolveResponse{
+			Solutions: make([]api.Grid, 0),
+			Error:     newError(requestID(req), api.ErrorCodeInvalidRequest, err),
+		}
+	}
+
+	if request.TimeoutMs > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(request.TimeoutMs)*time.Millisecond)
+		defer cancel()
+	}
+
+	response, err := solve(ctx, request)
+	if err != nil {
+		_, response.Error = solveError(requestID(req), err)
+	}
+
+	return *response
+}
+
+// readBatchRequest reads the batch from the body of the request, which
+// is either a BatchSolveRequest or newline delimited SolveRequests.
+func readBatchRequest(req *http.Request) (*api.BatchSolveRequest, error) {
+	defer func() {
+		if err := req.Body.Close(); err != nil {
+			log.Err(err).Msg("failed to close body")
+		}
+	}()
+
+	batch := &api.BatchSolveRequest{}
+	mediaType, _, _ := mime.ParseMediaType(req.Header.Get("Content-Type"))
+	if mediaType != contentTypeNDJSON {
+		err := json.NewDecoder(req.Body).Decode(batch)
+		return batch, err
+	}
+
+	var err error
+	query := req.URL.Query()
+	if batch.TimeoutMs, err = queryInt(query.Get("timeout_ms")); err != nil {
+		return nil, newFieldError("timeout_ms", "invalid timeout_ms: %v", err)
+	}
+	if batch.Parallelism, err = queryInt(query.Get("parallelism")); err != nil {
+		return nil, newFieldError("parallelism", "invalid parallelism: %v", err)
+	}
+
+	decoder := json.NewDecoder(req.Body)
+	for {
+		request := api.SolveRequest{}
+		err := decoder.Decode(&request)
+		if errors.Is(err, io.EOF) {
+			return batch, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		batch.Requests = append(batch.Requests, request)
+	}
+}
+
+// queryInt parses an optional integer query parameter.
+func queryInt(value string) (int, error) {
+	if value == "" {
+		return 0, nil
+	}
+
+	return strconv.Atoi(value)
+}
+
+// validateBatchRequest validates the fields of the batch. The requests
+// are validated individually when they are solved.
+func validateBatchRequest(batch *api.BatchSolveRequest) error {
+	switch {
+	case len(batch.Requests) == 0:
+		return newFieldError("requests", "batch contains no requests")
+	case batch.TimeoutMs < 0:
+		return newFieldError("timeout_ms", "invalid timeout_ms %d", batch.TimeoutMs)
+	case batch.Parallelism < 0:
+		return newFieldError("parallelism", "invalid parallelism %d", batch.Parallelism)
+	}
+
+	return nil
+}
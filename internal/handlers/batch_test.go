@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/PeterEFinch/sudoku-solver/api"
+	"github.com/PeterEFinch/sudoku-solver/internal/middleware"
+)
+
+func TestSolveBatch(t *testing.T) {
+	solved := `{"timeout_ms": 5000, "grid": ` + difficultGrid + `}`
+	unsolvable := `{"timeout_ms": 5000, "grid": ` + unsolvableGrid + `}`
+	invalid := `{"grid": [[1]]}`
+
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+	}{
+		{"json", "application/json", `{"parallelism": 2, "requests": [` + solved + `,` + unsolvable + `,` + invalid + `]}`},
+		{"ndjson", contentTypeNDJSON, strings.Join([]string{solved, unsolvable, invalid}, "\n")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/solve/batch?parallelism=2", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", tt.contentType)
+			rw := httptest.NewRecorder()
+
+			SolveBatch(4)(rw, req)
+
+			if rw.Code != http.StatusOK {
+				t.Fatalf("expected status %d, got %d: %s", http.StatusOK, rw.Code, rw.Body.String())
+			}
+
+			response := &api.BatchSolveResponse{}
+			err := json.Unmarshal(rw.Body.Bytes(), response)
+			if err != nil {
+				t.Fatalf("failed to unmarshal response: %v", err)
+			}
+
+			if len(response.Results) != 3 {
+				t.Fatalf("expected 3 results, got %d", len(response.Results))
+			}
+
+			if len(response.Results[0].Solutions) != 1 || response.Results[0].Error != nil {
+				t.Fatalf("expected the first request to be solved, got %+v", response.Results[0].Error)
+			}
+
+			for i, code := range []string{api.ErrorCodeNoSolution, api.ErrorCodeInvalidRequest} {
+				result := response.Results[i+1]
+				if result.Error == nil || result.Error.Code != code {
+					t.Fatalf("expected result %d to have error code %q, got %+v", i+1, code, result.Error)
+				}
+			}
+		})
+	}
+}
+
+func TestSolveBatch_ConcurrencyLimit(t *testing.T) {
+	limiter := middleware.NewConcurrencyLimiter(1, 0)
+	solved := `{"timeout_ms": 5000, "grid": ` + difficultGrid + `}`
+	batch := func(parallelism string) *api.BatchSolveResponse {
+		body := `{"parallelism": ` + parallelism + `, "requests": [` + solved + `,` + solved + `]}`
+		req := httptest.NewRequest(http.MethodPost, "/solve/batch", strings.NewReader(body))
+		rw := httptest.NewRecorder()
+		limiter.Share(SolveBatch(2)).ServeHTTP(rw, req)
+
+		response := &api.BatchSolveResponse{}
+		err := json.Unmarshal(rw.Body.Bytes(), response)
+		if err != nil {
+			t.Fatalf("failed to unmarshal response: %v", err)
+		}
+
+		return response
+	}
+
+	// Every solve of the batch needs a slot of its own
+	release, err := limiter.Acquire(context.Background())
+	if err != nil {
+		t.Fatalf("failed to acquire slot: %v", err)
+	}
+	for i, result := range batch("2").Results {
+		if result.Error == nil || result.Error.Code != api.ErrorCodeOverloaded {
+			t.Fatalf("expected result %d to have error code %q, got %+v", i, api.ErrorCodeOverloaded, result.Error)
+		}
+	}
+	release()
+
+	// Solves beyond the limit are rejected as there is no queue
+	for i, result := range batch("1").Results {
+		if len(result.Solutions) != 1 {
+			t.Fatalf("expected result %d to be solved once a slot is free, got %+v", i, result.Error)
+		}
+	}
+}
@@ -0,0 +1,174 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/rs/zerolog/log"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/PeterEFinch/sudoku-solver/api"
+	"github.com/PeterEFinch/sudoku-solver/internal/auth"
+	"github.com/PeterEFinch/sudoku-solver/internal/middleware"
+	"github.com/PeterEFinch/sudoku-solver/internal/sudoku"
+)
+
+// solveErrors maps the errors of the solver to the status and code
+// returned to the client.
+var solveErrors = []struct {
+	err    error
+	status int
+	code   string
+}{
+	{sudoku.ErrInvalidGrid, http.StatusBadRequest, api.ErrorCodeInvalidGrid},
+	{sudoku.ErrNoSolution, http.StatusUnprocessableEntity, api.ErrorCodeNoSolution},
+	{sudoku.ErrMultipleSolutions, http.StatusUnprocessableEntity, api.ErrorCodeMultipleSolutions},
+	{sudoku.ErrGenerationFailed, http.StatusServiceUnavailable, api.ErrorCodeGenerationFailed},
+	{sudoku.ErrGuessRequired, http.StatusConflict, api.ErrorCodeGuessRequired},
+	{sudoku.ErrTimeout, http.StatusGatewayTimeout, api.ErrorCodeTimeout},
+	{sudoku.ErrCancelled, http.StatusServiceUnavailable, api.ErrorCodeCancelled},
+	{auth.ErrQuotaExceeded, http.StatusTooManyRequests, api.ErrorCodeQuotaExceeded},
+	{middleware.ErrOverloaded, http.StatusServiceUnavailable, api.ErrorCodeOverloaded},
+}
+
+// fieldError is an error caused by a field of the request and,
+// optionally, a cell of the grid.
+type fieldError struct {
+	field string
+	cell  *api.Cell
+	err   error
+}
+
+func (e *fieldError) Error() string {
+	return e.err.Error()
+}
+
+func (e *fieldError) Unwrap() error {
+	return e.err
+}
+
+// newFieldError returns an error caused by the field of the request.
+func newFieldError(field, format string, args ...interface{}) error {
+	return &fieldError{field: field, err: fmt.Errorf(format, args...)}
+}
+
+// newCellError returns an error caused by a cell of the grid.
+func newCellError(row, column int, format string, args ...interface{}) error {
+	return &fieldError{
+		field: "grid",
+		cell:  &api.Cell{Row: row, Column: column},
+		err:   fmt.Errorf(format, args...),
+	}
+}
+
+// NotFound handles requests for unknown paths.
+func NotFound(rw http.ResponseWriter, req *http.Request) {
+	writeError(rw, req, http.StatusNotFound, api.ErrorCodeNotFound, fmt.Errorf("no handler for path %s", req.URL.Path))
+}
+
+// allowMethods writes an error and returns false if the method of the
+// request is not one of the given methods.
+func allowMethods(rw http.ResponseWriter, req *http.Request, methods ...string) bool {
+	for _, method := range methods {
+		if req.Method == method {
+			return true
+		}
+	}
+
+	for _, method := range methods {
+		rw.Header().Add("Allow", method)
+	}
+	writeError(rw, req, http.StatusMethodNotAllowed, api.ErrorCodeMethodNotAllowed, fmt.Errorf("method %s not allowed", req.Method))
+	return false
+}
+
+// solveError returns the status and api error for an error returned
+// while solving the request with the given id.
+func solveError(id string, err error) (int, *api.Error) {
+	for _, e := range solveErrors {
+		if errors.Is(err, e.err) {
+			return e.status, newError(id, e.code, err)
+		}
+	}
+
+	return http.StatusInternalServerError, newError(id, api.ErrorCodeInternal, err)
+}
+
+// newError converts the error of the request with the given id into an
+// api error with the given code, including the location of the error
+// if known.
+func newError(id, code string, err error) *api.Error {
+	apiErr := &api.Error{
+		Code:      code,
+		Message:   err.Error(),
+		RequestID: id,
+	}
+
+	var fieldErr *fieldError
+	if errors.As(err, &fieldErr) {
+		apiErr.Field = fieldErr.field
+		apiErr.Cell = fieldErr.cell
+	}
+
+	return apiErr
+}
+
+// requestID returns the id of the request.
+func requestID(req *http.Request) string {
+	return req.Header.Get(middleware.HeaderRequestID)
+}
+
+// writeError writes an error response with the given status.
+func writeError(rw http.ResponseWriter, req *http.Request, status int, code string, err error) {
+	writeJSON(rw, status, &api.ErrorResponse{
+		Error: newError(requestID(req), code, err),
+	})
+}
+
+// writeBodyError writes an error response for a body which could not
+// be read or decoded, see bodyError.
+func writeBodyError(rw http.ResponseWriter, req *http.Request, status int, code string, err error) {
+	status, code = bodyError(status, code, err)
+	writeError(rw, req, status, code, err)
+}
+
+// bodyError returns the status and code of an error reading the body
+// of a request. Bodies larger than the limit of the server are
+// rejected with 413, other errors with the given status and code.
+func bodyError(status int, code string, err error) (int, string) {
+	if errors.Is(err, middleware.ErrBodyTooLarge) {
+		return http.StatusRequestEntityTooLarge, api.ErrorCodeRequestTooLarge
+	}
+
+	return status, code
+}
+
+// writeTracedError writes an error response with the given status, or
+// that of bodyError, and marks the span of the request as failed.
+func writeTracedError(rw http.ResponseWriter, req *http.Request, span trace.Span, status int, code string, err error) {
+	status, code = bodyError(status, code, err)
+	span.SetAttributes(semconv.HTTPStatusCode(status))
+	span.SetStatus(codes.Error, err.Error())
+	writeError(rw, req, status, code, err)
+}
+
+// writeJSON writes the value as JSON with the given status.
+func writeJSON(rw http.ResponseWriter, status int, value interface{}) {
+	bs, err := json.Marshal(value)
+	if err != nil {
+		log.Err(err).Msg("failed to marshall response")
+		rw.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(status)
+	_, err = rw.Write(bs)
+	if err != nil {
+		log.Err(err).Msg("failed to write response")
+	}
+}
@@ -0,0 +1,162 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"math/rand"
+	"net/http"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/PeterEFinch/sudoku-solver/api"
+	"github.com/PeterEFinch/sudoku-solver/internal/auth"
+	"github.com/PeterEFinch/sudoku-solver/internal/middleware"
+	"github.com/PeterEFinch/sudoku-solver/internal/sudoku"
+)
+
+// Grade handles requests grading the difficulty of a sudoku.
+func Grade(rw http.ResponseWriter, req *http.Request) {
+	if !allowMethods(rw, req, http.MethodPost) {
+		return
+	}
+
+	request := &api.GradeRequest{}
+	err := json.NewDecoder(req.Body).Decode(request)
+	if err != nil {
+		log.Err(err).Msg("failed to unmarshal request")
+		writeBodyError(rw, req, http.StatusBadRequest, api.ErrorCodeMalformedRequest, err)
+		return
+	}
+
+	err = validateGrid(request.Grid)
+	if err == nil && request.TimeoutMs < 0 {
+		err = newFieldError("timeout_ms", "invalid timeout_ms %d", request.TimeoutMs)
+	}
+	if err != nil {
+		log.Err(err).Msg("bad request")
+		writeError(rw, req, http.StatusBadRequest, api.ErrorCodeInvalidRequest, err)
+		return
+	}
+
+	ctx, cancel := withTimeoutMs(req.Context(), request.TimeoutMs)
+	defer cancel()
+
+	var grade sudoku.Grade
+	grid, err := toGrid(request.Grid)
+	if err == nil {
+		grade, err = standardGrade(ctx, grid)
+	}
+	if err != nil {
+		status, apiErr := solveError(requestID(req), err)
+		log.Err(err).Str("code", apiErr.Code).Msg("failed to grade")
+		writeJSON(rw, status, &api.ErrorResponse{Error: apiErr})
+		return
+	}
+
+	writeJSON(rw, http.StatusOK, &api.GradeResponse{
+		Difficulty: string(grade.Difficulty),
+		Stats:      fromStats(grade.Stats, true),
+	})
+}
+
+// Generate handles requests generating a sudoku.
+func Generate(rw http.ResponseWriter, req *http.Request) {
+	if !allowMethods(rw, req, http.MethodPost) {
+		return
+	}
+
+	request := &api.GenerateRequest{}
+	err := json.NewDecoder(req.Body).Decode(request)
+	if err != nil {
+		log.Err(err).Msg("failed to unmarshal request")
+		writeBodyError(rw, req, http.StatusBadRequest, api.ErrorCodeMalformedRequest, err)
+		return
+	}
+
+	difficulty, err := sudoku.ParseDifficulty(request.Difficulty)
+	switch {
+	case err != nil:
+		err = newFieldError("difficulty", "invalid difficulty %q", request.Difficulty)
+	case request.TimeoutMs < 0:
+		err = newFieldError("timeout_ms", "invalid timeout_ms %d", request.TimeoutMs)
+	}
+	if err != nil {
+		log.Err(err).Msg("bad request")
+		writeError(rw, req, http.StatusBadRequest, api.ErrorCodeInvalidRequest, err)
+		return
+	}
+
+	ctx, cancel := withTimeoutMs(req.Context(), request.TimeoutMs)
+	defer cancel()
+
+	seed := request.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+
+	puzzle, solution, err := generate(ctx, difficulty, rand.New(rand.NewSource(seed)))
+	if err != nil {
+		status, apiErr := solveError(requestID(req), err)
+		log.Err(err).Str("code", apiErr.Code).Msg("failed to generate")
+		writeJSON(rw, status, &api.ErrorResponse{Error: apiErr})
+		return
+	}
+
+	writeJSON(rw, http.StatusOK, &api.GenerateResponse{
+		Puzzle:   fromGrid(puzzle),
+		Solution: fromGrid(solution),
+		Seed:     seed,
+	})
+}
+
+// standardGrade grades the grid once it has a slot of the concurrency
+// limit, charging the time to the quota of the client.
+func standardGrade(ctx context.Context, grid sudoku.Grid) (sudoku.Grade, error) {
+	release, err := acquireSolve(ctx)
+	if err != nil {
+		return sudoku.Grade{}, err
+	}
+	defer release()
+
+	ctx, settle, err := auth.Meter(ctx)
+	if err != nil {
+		return sudoku.Grade{}, err
+	}
+
+	grade, err := sudoku.StandardGrade(ctx, grid)
+	return grade, settle(err)
+}
+
+// generate generates a puzzle of the difficulty once it has a slot of
+// the concurrency limit, charging the time to the quota of the client.
+func generate(ctx context.Context, difficulty sudoku.Difficulty, rng *rand.Rand) (sudoku.Grid, sudoku.Grid, error) {
+	release, err := acquireSolve(ctx)
+	if err != nil {
+		return sudoku.Grid{}, sudoku.Grid{}, err
+	}
+	defer release()
+
+	ctx, settle, err := auth.Meter(ctx)
+	if err != nil {
+		return sudoku.Grid{}, sudoku.Grid{}, err
+	}
+
+	puzzle, solution, err := sudoku.Generate(ctx, difficulty, rng)
+	return puzzle, solution, settle(err)
+}
+
+// withTimeoutMs returns a context with the timeout in milliseconds. If
+// the timeout is zero the default timeout of the server is used, or a
+// cancellable context is returned if there is none.
+func withTimeoutMs(ctx context.Context, timeoutMs int) (context.Context, context.CancelFunc) {
+	if timeoutMs > 0 {
+		return context.WithTimeout(ctx, time.Duration(timeoutMs)*time.Millisecond)
+	}
+
+	if timeout, ok := middleware.DefaultTimeoutFrom(ctx); ok {
+		return context.WithTimeout(ctx, timeout)
+	}
+
+	return context.WithCancel(ctx)
+}
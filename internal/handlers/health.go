@@ -2,9 +2,52 @@ package handlers
 
 import (
 	"net/http"
+	"sync/atomic"
+
+	"github.com/PeterEFinch/sudoku-solver/api"
+	"github.com/PeterEFinch/sudoku-solver/internal/buildinfo"
 )
 
-// Health handles requests for the health of the service.
-func Health(response http.ResponseWriter, _ *http.Request) {
-	response.WriteHeader(http.StatusOK)
+// Live handles liveness probes, which succeed as long as the server is
+// serving requests.
+func Live(rw http.ResponseWriter, _ *http.Request) {
+	writeJSON(rw, http.StatusOK, &api.HealthResponse{Status: api.HealthStatusOK})
+}
+
+// Readiness reports whether the server accepts new work, which it stops
+// doing once it starts draining to shut down. It is safe for concurrent
+// use.
+type Readiness struct {
+	draining int32
+}
+
+// Drain marks the server as draining, failing the readiness probes so
+// that load balancers stop sending it requests.
+func (r *Readiness) Drain() {
+	atomic.StoreInt32(&r.draining, 1)
+}
+
+// Draining returns true once Drain has been called.
+func (r *Readiness) Draining() bool {
+	return atomic.LoadInt32(&r.draining) == 1
+}
+
+// Ready handles readiness probes, responding with 503 once the server
+// is draining.
+func (r *Readiness) Ready(rw http.ResponseWriter, _ *http.Request) {
+	if r.Draining() {
+		writeJSON(rw, http.StatusServiceUnavailable, &api.HealthResponse{Status: api.HealthStatusDraining})
+		return
+	}
+
+	writeJSON(rw, http.StatusOK, &api.HealthResponse{Status: api.HealthStatusOK})
+}
+
+// Version handles requests for the build info of the server.
+func Version(rw http.ResponseWriter, req *http.Request) {
+	if !allowMethods(rw, req, http.MethodGet) {
+		return
+	}
+
+	writeJSON(rw, http.StatusOK, buildinfo.Get())
 }
@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/PeterEFinch/sudoku-solver/api"
+)
+
+func TestReadiness(t *testing.T) {
+	readiness := &Readiness{}
+	mux := NewServeMux(1, nil, readiness)
+
+	probe := func(path string) (int, string) {
+		rw := httptest.NewRecorder()
+		mux.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, path, nil))
+
+		response := &api.HealthResponse{}
+		if err := json.Unmarshal(rw.Body.Bytes(), response); err != nil {
+			t.Fatalf("failed to unmarshal response: %v", err)
+		}
+		return rw.Code, response.Status
+	}
+
+	tests := []struct {
+		name     string
+		draining bool
+		path     string
+		status   int
+		health   string
+	}{
+		{"live", false, "/livez", http.StatusOK, api.HealthStatusOK},
+		{"ready", false, "/readyz", http.StatusOK, api.HealthStatusOK},
+		{"live while draining", true, "/livez", http.StatusOK, api.HealthStatusOK},
+		{"not ready while draining", true, "/readyz", http.StatusServiceUnavailable, api.HealthStatusDraining},
+		{"health while draining", true, "/health", http.StatusServiceUnavailable, api.HealthStatusDraining},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.draining {
+				readiness.Drain()
+			}
+
+			status, health := probe(tt.path)
+			if status != tt.status || health != tt.health {
+				t.Fatalf("expected %d %q, got %d %q", tt.status, tt.health, status, health)
+			}
+		})
+	}
+}
+
+func TestVersion(t *testing.T) {
+	rw := httptest.NewRecorder()
+	Version(rw, httptest.NewRequest(http.MethodGet, "/version", nil))
+
+	info := &api.BuildInfo{}
+	if err := json.Unmarshal(rw.Body.Bytes(), info); err != nil || info.Version == "" || info.GoVersion == "" {
+		t.Fatalf("expected the version and go version, got %s", rw.Body.String())
+	}
+}
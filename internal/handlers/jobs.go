@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/PeterEFinch/sudoku-solver/api"
+	"github.com/PeterEFinch/sudoku-solver/internal/jobs"
+	"github.com/PeterEFinch/sudoku-solver/internal/metrics"
+	"github.com/PeterEFinch/sudoku-solver/internal/sudoku"
+)
+
+// Jobs returns a handler for the asynchronous solve endpoints. Jobs
+// are created by posting a solve request to /jobs, and are polled and
+// cancelled using GET and DELETE requests to /jobs/{id}.
+func Jobs(manager *jobs.Manager) http.HandlerFunc {
+	return func(rw http.ResponseWriter, req *http.Request) {
+		id := strings.Trim(strings.TrimPrefix(req.URL.Path, "/jobs"), "/")
+		if id == "" {
+			if allowMethods(rw, req, http.MethodPost) {
+				createJob(rw, req, manager)
+			}
+			return
+		}
+
+		if !allowMethods(rw, req, http.MethodGet, http.MethodDelete) {
+			return
+		}
+
+		var job api.Job
+		var err error
+		if req.Method == http.MethodGet {
+			job, err = manager.Get(req.Context(), id)
+		} else {
+			job, err = manager.Cancel(req.Context(), id)
+		}
+
+		switch {
+		case errors.Is(err, jobs.ErrNotFound):
+			writeError(rw, req, http.StatusNotFound, api.ErrorCodeNotFound, err)
+		case err != nil:
+			log.Err(err).Str("job", id).Msg("failed to load job")
+			writeError(rw, req, http.StatusInternalServerError, api.ErrorCodeInternal, err)
+		default:
+			writeJSON(rw, http.StatusOK, job)
+		}
+	}
+}
+
+// createJob validates the solve request and starts a job solving it.
+func createJob(rw http.ResponseWriter, req *http.Request, manager *jobs.Manager) {
+	request := &api.SolveRequest{}
+	err := json.NewDecoder(req.Body).Decode(request)
+	if err != nil {
+		log.Err(err).Msg("failed to unmarshal request")
+		writeBodyError(rw, req, http.StatusBadRequest, api.ErrorCodeMalformedRequest, err)
+		return
+	}
+
+	err = validateSolveRequest(request)
+	if err != nil {
+		metrics.SolveRejected()
+		log.Err(err).Msg("bad request")
+		writeError(rw, req, http.StatusBadRequest, api.ErrorCodeInvalidRequest, err)
+		return
+	}
+
+	job, err := manager.Create(req.Context(), *request)
+	if errors.Is(err, jobs.ErrClosed) {
+		writeError(rw, req, http.StatusServiceUnavailable, api.ErrorCodeCancelled, err)
+		return
+	}
+	if err != nil {
+		log.Err(err).Msg("failed to create job")
+		writeError(rw, req, http.StatusInternalServerError, api.ErrorCodeInternal, err)
+		return
+	}
+
+	rw.Header().Set("Location", "/jobs/"+job.ID)
+	writeJSON(rw, http.StatusAccepted, job)
+}
+
+// JobRunner returns the runner used by the job manager to solve the
+// requests of jobs.
+func JobRunner() jobs.Runner {
+	return func(ctx context.Context, request *api.SolveRequest, yield func(solution api.Grid), report func(nodes int)) api.SolveResponse {
+		summary, err := stream(ctx, request, func(solution api.Grid) bool {
+			yield(solution)
+			return true
+		}, sudoku.WithProgress(report))
+		report(summary.Stats.Nodes)
+
+		response := api.SolveResponse{
+			Completed: completed(summary, err),
+			StoppedBy: string(summary.Limit),
+			Stats:     fromStats(summary.Stats, request.Stats),
+		}
+		if err != nil {
+			_, response.Error = solveError("", err)
+		}
+
+		return response
+	}
+}
@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/PeterEFinch/sudoku-solver/api"
+	"github.com/PeterEFinch/sudoku-solver/internal/jobs"
+)
+
+func TestJobs_Progress(t *testing.T) {
+	manager := jobs.NewManager(jobs.NewMemoryStore(), JobRunner(), jobs.WithSaveInterval(0), jobs.WithMaxSolutions(0))
+	defer manager.Close()
+	handler := Jobs(manager)
+
+	serve := func(method, path, body string) *api.Job {
+		rw := httptest.NewRecorder()
+		handler(rw, httptest.NewRequest(method, path, strings.NewReader(body)))
+		if rw.Code != http.StatusOK && rw.Code != http.StatusAccepted {
+			t.Fatalf("expected a job, got status %d: %s", rw.Code, rw.Body.String())
+		}
+
+		job := &api.Job{}
+		if err := json.Unmarshal(rw.Body.Bytes(), job); err != nil {
+			t.Fatalf("failed to unmarshal job: %v", err)
+		}
+		return job
+	}
+
+	// The empty grid has more solutions than can be found before the job
+	// is cancelled
+	empty := strings.Repeat(`[0,0,0,0,0,0,0,0,0],`, 8) + `[0,0,0,0,0,0,0,0,0]`
+	job := serve(http.MethodPost, "/jobs", `{"timeout_ms": 60000, "grid": [`+empty+`]}`)
+
+	// Waits for the progress of the running job
+	for i := 0; job.Progress.Nodes < 1000 || job.Progress.Solutions == 0; i++ {
+		if i == 100 || job.Finished() {
+			t.Fatalf("expected the progress of a running job, got %+v", job.Progress)
+		}
+		time.Sleep(10 * time.Millisecond)
+		job = serve(http.MethodGet, "/jobs/"+job.ID, "")
+	}
+
+	job = serve(http.MethodDelete, "/jobs/"+job.ID, "")
+	if job.Status != api.JobStatusCancelled || job.Progress.Solutions != len(job.Result.Solutions) || job.Progress.Nodes < job.Progress.Solutions {
+		t.Fatalf("expected the progress of the cancelled job to match its result, got %+v with %d solutions", job.Progress, len(job.Result.Solutions))
+	}
+}
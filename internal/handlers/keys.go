@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/PeterEFinch/sudoku-solver/api"
+	"github.com/PeterEFinch/sudoku-solver/internal/auth"
+)
+
+// Keys returns a handler administering the API keys of the store. Keys
+// are issued by posting to /admin/keys, listed using GET /admin/keys
+// and revoked using DELETE /admin/keys/{id}. The handler does not
+// authenticate the admin itself, see auth.RequireAdmin.
+func Keys(store *auth.Store) http.HandlerFunc {
+	return func(rw http.ResponseWriter, req *http.Request) {
+		id := strings.Trim(strings.TrimPrefix(req.URL.Path, "/admin/keys"), "/")
+		if id != "" {
+			if allowMethods(rw, req, http.MethodDelete) {
+				revokeKey(rw, req, store, id)
+			}
+			return
+		}
+
+		if !allowMethods(rw, req, http.MethodGet, http.MethodPost) {
+			return
+		}
+
+		if req.Method == http.MethodPost {
+			createKey(rw, req, store)
+			return
+		}
+
+		stored := store.List()
+		list := &api.APIKeyList{Keys: make([]api.APIKey, len(stored))}
+		for i, key := range stored {
+			list.Keys[i] = fromKey(store, key)
+		}
+		writeJSON(rw, http.StatusOK, list)
+	}
+}
+
+// createKey issues a key for the request.
+func createKey(rw http.ResponseWriter, req *http.Request, store *auth.Store) {
+	request := &api.CreateAPIKeyRequest{}
+	err := json.NewDecoder(req.Body).Decode(request)
+	if err != nil {
+		log.Err(err).Msg("failed to unmarshal request")
+		writeBodyError(rw, req, http.StatusBadRequest, api.ErrorCodeMalformedRequest, err)
+		return
+	}
+
+	switch {
+	case strings.TrimSpace(request.Name) == "":
+		err = newFieldError("name", "name is required")
+	case request.DailyQuotaMs < 0:
+		err = newFieldError("daily_quota_ms", "invalid daily_quota_ms %d", request.DailyQuotaMs)
+	}
+	if err != nil {
+		log.Err(err).Msg("bad request")
+		writeError(rw, req, http.StatusBadRequest, api.ErrorCodeInvalidRequest, err)
+		return
+	}
+
+	secret, key, err := store.Create(request.Name, request.DailyQuotaMs)
+	if err != nil {
+		log.Err(err).Msg("failed to create key")
+		writeError(rw, req, http.StatusInternalServerError, api.ErrorCodeInternal, err)
+		return
+	}
+
+	log.Info().Str("key", key.ID).Str("name", key.Name).Msg("created api key")
+	rw.Header().Set("Location", "/admin/keys/"+key.ID)
+	writeJSON(rw, http.StatusCreated, &api.CreateAPIKeyResponse{
+		APIKey: fromKey(store, key),
+		Key:    secret,
+	})
+}
+
+// revokeKey revokes the key with the id.
+func revokeKey(rw http.ResponseWriter, req *http.Request, store *auth.Store, id string) {
+	key, err := store.Revoke(id)
+	switch {
+	case errors.Is(err, auth.ErrKeyNotFound):
+		writeError(rw, req, http.StatusNotFound, api.ErrorCodeNotFound, err)
+	case err != nil:
+		log.Err(err).Str("key", id).Msg("failed to revoke key")
+		writeError(rw, req, http.StatusInternalServerError, api.ErrorCodeInternal, err)
+	default:
+		log.Info().Str("key", key.ID).Msg("revoked api key")
+		writeJSON(rw, http.StatusOK, fromKey(store, key))
+	}
+}
+
+// fromKey converts a stored key into its api representation.
+func fromKey(store *auth.Store, key auth.Key) api.APIKey {
+	return api.APIKey{
+		ID:           key.ID,
+		Name:         key.Name,
+		DailyQuotaMs: key.DailyQuotaMs,
+		UsedTodayMs:  store.UsedToday(key.ID).Milliseconds(),
+		CreatedAt:    key.CreatedAt,
+		RevokedAt:    key.RevokedAt,
+	}
+}
@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/PeterEFinch/sudoku-solver/api"
+	"github.com/PeterEFinch/sudoku-solver/internal/auth"
+	"github.com/PeterEFinch/sudoku-solver/internal/middleware"
+)
+
+func TestKeys(t *testing.T) {
+	store, err := auth.NewStore("", nil)
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+
+	admin := Keys(store)
+	solve := middleware.Chain(http.HandlerFunc(Solve), auth.Authenticate(store))
+
+	rw := httptest.NewRecorder()
+	admin(rw, httptest.NewRequest(http.MethodPost, "/admin/keys", strings.NewReader(`{"name": "ci", "daily_quota_ms": 1}`)))
+	if rw.Code != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusCreated, rw.Code, rw.Body.String())
+	}
+
+	created := &api.CreateAPIKeyResponse{}
+	if err := json.Unmarshal(rw.Body.Bytes(), created); err != nil || created.Key == "" {
+		t.Fatalf("expected the response to contain the key, got %s", rw.Body.String())
+	}
+
+	// Solves until the quota of 1ms is used
+	var response *api.SolveResponse
+	for i := 0; i < 100; i++ {
+		req := httptest.NewRequest(http.MethodPost, "/solve", strings.NewReader(`{"timeout_ms": 5000, "grid": `+difficultGrid+`}`))
+		req.Header.Set(auth.HeaderAPIKey, created.Key)
+		rw = httptest.NewRecorder()
+		solve.ServeHTTP(rw, req)
+
+		response = &api.SolveResponse{}
+		if err := json.Unmarshal(rw.Body.Bytes(), response); err != nil {
+			t.Fatalf("failed to unmarshal response: %v", err)
+		}
+		if rw.Code == http.StatusTooManyRequests {
+			break
+		}
+	}
+
+	if response.Error == nil || response.Error.Code != api.ErrorCodeQuotaExceeded {
+		t.Fatalf("expected the quota to be exceeded, got %+v", response.Error)
+	}
+
+	rw = httptest.NewRecorder()
+	admin(rw, httptest.NewRequest(http.MethodGet, "/admin/keys", nil))
+	list := &api.APIKeyList{}
+	if err := json.Unmarshal(rw.Body.Bytes(), list); err != nil || len(list.Keys) != 1 || list.Keys[0].UsedTodayMs < 1 {
+		t.Fatalf("expected the key to have used its quota, got %s", rw.Body.String())
+	}
+
+	rw = httptest.NewRecorder()
+	admin(rw, httptest.NewRequest(http.MethodDelete, "/admin/keys/"+created.ID, nil))
+	if rw.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, rw.Code, rw.Body.String())
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/solve", strings.NewReader(`{"grid": `+difficultGrid+`}`))
+	req.Header.Set(auth.HeaderAPIKey, created.Key)
+	rw = httptest.NewRecorder()
+	solve.ServeHTTP(rw, req)
+	if rw.Code != http.StatusUnauthorized {
+		t.Fatalf("expected revoked key to be rejected with %d, got %d", http.StatusUnauthorized, rw.Code)
+	}
+
+	rw = httptest.NewRecorder()
+	admin(rw, httptest.NewRequest(http.MethodDelete, "/admin/keys/unknown", nil))
+	if rw.Code != http.StatusNotFound {
+		t.Fatalf("expected status %d, got %d", http.StatusNotFound, rw.Code)
+	}
+}
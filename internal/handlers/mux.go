@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/PeterEFinch/sudoku-solver/internal/jobs"
+	"github.com/PeterEFinch/sudoku-solver/internal/middleware"
+)
+
+// NewServeMux returns a mux routing every endpoint of the HTTP API.
+// Batches are solved with at most batchParallelism concurrent solves
+// and asynchronous jobs are run by the manager. The readiness probes
+// fail once readiness is draining. The endpoints which run the solver
+// synchronously are wrapped by solveMiddlewares, e.g. to set their
+// timeouts. Each solve, including those of batches, jobs and play
+// sessions, waits for a slot of the concurrency limiter of its context,
+// see middleware.ConcurrencyLimiter.Share.
+func NewServeMux(batchParallelism int, jobManager *jobs.Manager, readiness *Readiness, solveMiddlewares ...middleware.Middleware) *http.ServeMux {
+	jobsHandler := Jobs(jobManager)
+	solving := func(handler http.HandlerFunc) http.Handler {
+		return middleware.Chain(handler, solveMiddlewares...)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", NotFound)
+	mux.HandleFunc("/health", readiness.Ready)
+	mux.HandleFunc("/livez", Live)
+	mux.HandleFunc("/readyz", readiness.Ready)
+	mux.HandleFunc("/version", Version)
+	mux.Handle("/solve", solving(Solve))
+	mux.Handle("/solve/batch", solving(SolveBatch(batchParallelism)))
+	mux.Handle("/grade", solving(Grade))
+	mux.Handle("/generate", solving(Generate))
+	mux.HandleFunc("/jobs", jobsHandler)
+	mux.HandleFunc("/jobs/", jobsHandler)
+	mux.HandleFunc("/play", Play)
+	mux.HandleFunc("/openapi.json", OpenAPI)
+	return mux
+}
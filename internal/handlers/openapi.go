@@ -0,0 +1,22 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/PeterEFinch/sudoku-solver/api"
+)
+
+// OpenAPI handles requests for the OpenAPI document of the API.
+func OpenAPI(rw http.ResponseWriter, req *http.Request) {
+	if !allowMethods(rw, req, http.MethodGet) {
+		return
+	}
+
+	rw.Header().Set("Content-Type", "application/json")
+	_, err := rw.Write(api.OpenAPI)
+	if err != nil {
+		log.Err(err).Msg("failed to write response")
+	}
+}
@@ -0,0 +1,272 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/rs/zerolog/log"
+
+	"github.com/PeterEFinch/sudoku-solver/api"
+	"github.com/PeterEFinch/sudoku-solver/internal/play"
+	"github.com/PeterEFinch/sudoku-solver/internal/sudoku"
+)
+
+const (
+	// maxPlayMessageSize is the maximum size in bytes of a message
+	// sent by a player, large enough for a start action with a grid.
+	maxPlayMessageSize = 4096
+
+	// playActionTimeout limits the time spent generating a puzzle or
+	// finding a hint for a single action.
+	playActionTimeout = 10 * time.Second
+)
+
+// errNoSession is returned for actions sent before a session is started.
+var errNoSession = errors.New("no session started")
+
+// moveErrors are the errors returned for moves which are not allowed.
+var moveErrors = []error{
+	play.ErrGivenCell,
+	play.ErrFilledCell,
+	sudoku.ErrInvalidRow,
+	sudoku.ErrInvalidColumn,
+	sudoku.ErrInvalidValue,
+}
+
+var upgrader = websocket.Upgrader{}
+
+// Play handles websocket connections for interactive play sessions.
+// Each message of the player is answered with a single event, see
+// api.PlayMessage and api.PlayEvent.
+func Play(rw http.ResponseWriter, req *http.Request) {
+	if !allowMethods(rw, req, http.MethodGet) {
+		return
+	}
+
+	conn, err := upgrader.Upgrade(rw, req, nil)
+	if err != nil {
+		// The upgrader has already written an error response
+		log.Err(err).Msg("failed to upgrade connection")
+		return
+	}
+	defer conn.Close()
+	conn.SetReadLimit(maxPlayMessageSize)
+
+	p := &player{
+		id:  requestID(req),
+		rng: rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+
+	// Stops reading once the context is done, e.g. as the server drains,
+	// after replying to the message in flight, whose action is interrupted
+	ctx := req.Context()
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		select {
+		case <-ctx.Done():
+			_ = conn.SetReadDeadline(time.Now())
+		case <-stop:
+		}
+	}()
+
+	for {
+		message := &api.PlayMessage{}
+		err := conn.ReadJSON(message)
+		var event api.PlayEvent
+		switch {
+		case isMalformed(err):
+			event = p.error(api.ErrorCodeMalformedRequest, err)
+		case websocket.IsCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway):
+			return
+		case err != nil && ctx.Err() != nil:
+			message := websocket.FormatCloseMessage(websocket.CloseGoingAway, "server shutting down")
+			_ = conn.WriteControl(websocket.CloseMessage, message, time.Now().Add(time.Second))
+			return
+		case err != nil:
+			log.Err(err).Msg("failed to read message")
+			return
+		default:
+			event = p.handle(ctx, message)
+		}
+
+		err = conn.WriteJSON(event)
+		if err != nil {
+			log.Err(err).Msg("failed to write event")
+			return
+		}
+	}
+}
+
+// isMalformed returns true if a message was read but could not be
+// unmarshalled, in which case the connection can still be used.
+func isMalformed(err error) bool {
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	return errors.As(err, &syntaxErr) || errors.As(err, &typeErr) || errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// player holds the session of a websocket connection.
+type player struct {
+	id      string
+	rng     *rand.Rand
+	session *play.Session
+}
+
+// handle applies the action of the message to the session and returns
+// the event sent in reply.
+func (p *player) handle(ctx context.Context, message *api.PlayMessage) api.PlayEvent {
+	if message.Action != api.PlayActionStart && p.session == nil {
+		return p.error(api.ErrorCodeInvalidRequest, &fieldError{field: "action", err: errNoSession})
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, playActionTimeout)
+	defer cancel()
+
+	var err error
+	switch message.Action {
+	case api.PlayActionStart:
+		err = p.start(ctx, message)
+	case api.PlayActionPlace:
+		err = p.session.Place(message.Row, message.Column, message.Value)
+	case api.PlayActionRemove:
+		err = p.session.Remove(message.Row, message.Column)
+	case api.PlayActionPencil:
+		err = p.session.TogglePencilMark(message.Row, message.Column, message.Value)
+	case api.PlayActionHint:
+		return p.hint(ctx)
+	default:
+		err = newFieldError("action", "unknown action %q", message.Action)
+		return p.error(api.ErrorCodeInvalidRequest, err)
+	}
+
+	if err != nil {
+		return p.moveError(err, message)
+	}
+
+	return api.PlayEvent{Event: api.PlayEventState, State: p.state()}
+}
+
+// start starts a new session for the puzzle of the message, generating
+// one if the message has no grid.
+func (p *player) start(ctx context.Context, message *api.PlayMessage) error {
+	var puzzle sudoku.Grid
+	if len(message.Grid) > 0 {
+		err := validateGrid(message.Grid)
+		if err != nil {
+			return err
+		}
+
+		puzzle, err = toGrid(message.Grid)
+		if err != nil {
+			return err
+		}
+	} else {
+		difficulty, err := sudoku.ParseDifficulty(message.Difficulty)
+		if err != nil {
+			return newFieldError("difficulty", "invalid difficulty %q", message.Difficulty)
+		}
+
+		puzzle, _, err = generate(ctx, difficulty, p.rng)
+		if err != nil {
+			return err
+		}
+	}
+
+	session, err := play.NewSession(puzzle)
+	if err != nil {
+		return err
+	}
+
+	p.session = session
+	return nil
+}
+
+// hint returns an event with the next entry the player could place.
+func (p *player) hint(ctx context.Context) api.PlayEvent {
+	release, err := acquireSolve(ctx)
+	if err != nil {
+		_, apiErr := solveError(p.id, err)
+		return api.PlayEvent{Event: api.PlayEventError, Error: apiErr}
+	}
+	hint, err := p.session.Hint(ctx)
+	release()
+	if errors.Is(err, sudoku.ErrGridCompleted) {
+		return p.error(api.ErrorCodeGridCompleted, err)
+	}
+	if err != nil {
+		_, apiErr := solveError(p.id, err)
+		return api.PlayEvent{Event: api.PlayEventError, Error: apiErr}
+	}
+
+	return api.PlayEvent{
+		Event: api.PlayEventHint,
+		Hint: &api.Hint{
+			Cell:      api.Cell{Row: hint.Row, Column: hint.Column},
+			Value:     hint.Value,
+			Technique: string(hint.Technique),
+		},
+	}
+}
+
+// state returns the current state of the session.
+func (p *player) state() *api.PlayState {
+	state := &api.PlayState{
+		Puzzle:      fromGrid(p.session.Puzzle()),
+		Grid:        fromGrid(p.session.Grid()),
+		PencilMarks: make([][][]int, sudoku.Size),
+		Conflicts:   []api.Cell{},
+		Completed:   p.session.Completed(),
+	}
+
+	for row := range state.PencilMarks {
+		state.PencilMarks[row] = make([][]int, sudoku.Size)
+		for column := range state.PencilMarks[row] {
+			marks := p.session.Marks(row, column)
+			if marks == nil {
+				marks = []int{}
+			}
+			state.PencilMarks[row][column] = marks
+		}
+	}
+
+	for _, cell := range p.session.Conflicts() {
+		state.Conflicts = append(state.Conflicts, api.Cell{Row: cell.Row, Column: cell.Column})
+	}
+
+	return state
+}
+
+// moveError returns an error event for an action which failed,
+// locating the cell of the message if the move is not allowed.
+func (p *player) moveError(err error, message *api.PlayMessage) api.PlayEvent {
+	for _, moveErr := range moveErrors {
+		if errors.Is(err, moveErr) {
+			err = &fieldError{
+				cell: &api.Cell{Row: message.Row, Column: message.Column},
+				err:  fmt.Errorf("invalid %s: %w", message.Action, err),
+			}
+			return p.error(api.ErrorCodeInvalidMove, err)
+		}
+	}
+
+	var fieldErr *fieldError
+	if errors.As(err, &fieldErr) {
+		return p.error(api.ErrorCodeInvalidRequest, err)
+	}
+
+	_, apiErr := solveError(p.id, err)
+	return api.PlayEvent{Event: api.PlayEventError, Error: apiErr}
+}
+
+// error returns an error event with the given code.
+func (p *player) error(code string, err error) api.PlayEvent {
+	return api.PlayEvent{Event: api.PlayEventError, Error: newError(p.id, code, err)}
+}
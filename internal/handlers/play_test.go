@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/PeterEFinch/sudoku-solver/api"
+	"github.com/PeterEFinch/sudoku-solver/internal/drain"
+)
+
+func TestPlay(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(Play))
+	defer server.Close()
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+	if err != nil {
+		t.Fatalf("failed to dial server: %v", err)
+	}
+	defer conn.Close()
+
+	tests := []struct {
+		name    string
+		message string
+		event   string
+		code    string
+	}{
+		{"not started", `{"action": "place", "row": 0, "column": 1, "value": 1}`, api.PlayEventError, api.ErrorCodeInvalidRequest},
+		{"malformed", `{"action": `, api.PlayEventError, api.ErrorCodeMalformedRequest},
+		{"start", `{"action": "start", "grid": ` + difficultGrid + `}`, api.PlayEventState, ""},
+		{"place", `{"action": "place", "row": 0, "column": 1, "value": 8}`, api.PlayEventState, ""},
+		{"given cell", `{"action": "place", "row": 0, "column": 0, "value": 1}`, api.PlayEventError, api.ErrorCodeInvalidMove},
+		{"pencil filled cell", `{"action": "pencil", "row": 0, "column": 1, "value": 2}`, api.PlayEventError, api.ErrorCodeInvalidMove},
+		{"remove", `{"action": "remove", "row": 0, "column": 1}`, api.PlayEventState, ""},
+		{"pencil", `{"action": "pencil", "row": 0, "column": 1, "value": 2}`, api.PlayEventState, ""},
+		{"hint", `{"action": "hint"}`, api.PlayEventHint, ""},
+		{"unknown action", `{"action": "undo"}`, api.PlayEventError, api.ErrorCodeInvalidRequest},
+	}
+
+	for _, tt := range tests {
+		err := conn.WriteMessage(websocket.TextMessage, []byte(tt.message))
+		if err != nil {
+			t.Fatalf("%s: failed to write message: %v", tt.name, err)
+		}
+
+		event := &api.PlayEvent{}
+		err = conn.ReadJSON(event)
+		if err != nil {
+			t.Fatalf("%s: failed to read event: %v", tt.name, err)
+		}
+
+		if event.Event != tt.event {
+			t.Fatalf("%s: expected event %q, got %+v", tt.name, tt.event, event)
+		}
+
+		if tt.code != "" && (event.Error == nil || event.Error.Code != tt.code) {
+			t.Fatalf("%s: expected error code %q, got %+v", tt.name, tt.code, event.Error)
+		}
+
+		switch tt.name {
+		case "place":
+			if len(event.State.Conflicts) != 2 || event.State.Grid[0][1] != 8 {
+				t.Fatalf("expected the placed digit to conflict with the given 8, got %+v", event.State)
+			}
+		case "pencil":
+			if len(event.State.Conflicts) != 0 || len(event.State.PencilMarks[0][1]) != 1 {
+				t.Fatalf("expected a single pencil mark and no conflicts, got %+v", event.State)
+			}
+		}
+	}
+}
+
+func TestPlay_Drain(t *testing.T) {
+	drainer := drain.New()
+	server := httptest.NewServer(drainer.Middleware(http.HandlerFunc(Play)))
+	defer server.Close()
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+	if err != nil {
+		t.Fatalf("failed to dial server: %v", err)
+	}
+	defer conn.Close()
+
+	// Waits for the connection to be tracked
+	err = conn.WriteMessage(websocket.TextMessage, []byte(`{"action": "start", "grid": `+difficultGrid+`}`))
+	if err == nil {
+		err = conn.ReadJSON(&api.PlayEvent{})
+	}
+	if err != nil {
+		t.Fatalf("failed to start session: %v", err)
+	}
+
+	if count := drainer.Drain(); count != 1 {
+		t.Fatalf("expected the play session to be interrupted, got %d", count)
+	}
+
+	_, _, err = conn.ReadMessage()
+	if !websocket.IsCloseError(err, websocket.CloseGoingAway) {
+		t.Fatalf("expected the connection to be closed as going away, got %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := drainer.Wait(ctx); err != nil {
+		t.Fatalf("expected the play session to finish, got %v", err)
+	}
+}
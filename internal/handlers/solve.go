@@ -2,124 +2,521 @@ package handlers
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"sort"
+	"strings"
 	"time"
 
-	"github.com/rs/zerolog/log"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+	"go.opentelemetry.io/otel/trace"
 
 	"github.com/PeterEFinch/sudoku-solver/api"
+	"github.com/PeterEFinch/sudoku-solver/internal/auth"
+	"github.com/PeterEFinch/sudoku-solver/internal/cache"
+	"github.com/PeterEFinch/sudoku-solver/internal/metrics"
+	"github.com/PeterEFinch/sudoku-solver/internal/middleware"
 	"github.com/PeterEFinch/sudoku-solver/internal/sudoku"
+	"github.com/PeterEFinch/sudoku-solver/internal/tracing"
 )
 
 // Solve handles requests solving a sudoku.
 func Solve(rw http.ResponseWriter, req *http.Request) {
+	if !allowMethods(rw, req, http.MethodPost) {
+		return
+	}
+
+	ctx, span := tracing.StartRequest(req, "POST /solve")
+	defer span.End()
+	logger := tracing.Logger(ctx)
+
 	reqBs, err := ioutil.ReadAll(req.Body)
 	if err != nil {
-		log.Err(err).Msg("failed to unmarshal request")
-		rw.WriteHeader(http.StatusInternalServerError)
+		logger.Err(err).Msg("failed to read request")
+		writeTracedError(rw, req, span, http.StatusInternalServerError, api.ErrorCodeInternal, err)
 		return
 	}
 
 	err = req.Body.Close()
 	if err != nil {
-		log.Err(err).Msg("failed to close body")
-		rw.WriteHeader(http.StatusInternalServerError)
+		logger.Err(err).Msg("failed to close body")
+		writeTracedError(rw, req, span, http.StatusInternalServerError, api.ErrorCodeInternal, err)
 		return
 	}
 
 	request := &api.SolveRequest{}
 	err = json.Unmarshal(reqBs, request)
 	if err != nil {
-		log.Err(err).Msg("failed to unmarshal request")
-		rw.WriteHeader(http.StatusBadRequest)
+		logger.Err(err).Msg("failed to unmarshal request")
+		writeTracedError(rw, req, span, http.StatusBadRequest, api.ErrorCodeMalformedRequest, err)
 		return
 	}
 
+	_, validation := tracing.Tracer().Start(ctx, "validate")
 	err = validateSolveRequest(request)
+	validation.End()
+	span.SetAttributes(attribute.Int("sudoku.clues", clueCount(request.Grid)))
 	if err != nil {
-		log.Err(err).Msg("bad request")
-		rw.WriteHeader(http.StatusBadRequest)
-		_, _ = rw.Write([]byte(err.Error()))
+		metrics.SolveRejected()
+		span.SetAttributes(attribute.String("sudoku.outcome", metrics.OutcomeInvalid))
+		logger.Err(err).Msg("bad request")
+		writeTracedError(rw, req, span, http.StatusBadRequest, api.ErrorCodeInvalidRequest, err)
 		return
 	}
 
-	timeout := time.Duration(request.TimeoutMs) * time.Millisecond
-	ctx, cancel := context.WithTimeout(req.Context(), timeout)
+	ctx, cancel := withTimeoutMs(ctx, request.TimeoutMs)
 	defer cancel()
 
-	response, err := solve(ctx, request)
-	if err != nil {
-		log.Err(err).Msg("solver failed to run")
-		rw.WriteHeader(http.StatusInternalServerError)
-		return
+	if req.Header.Get("Cache-Control") == "no-cache" {
+		request.NoCache = true
 	}
 
-	resBs, err := json.Marshal(response)
-	if err != nil {
-		log.Err(err).Msg("failed to marshall response")
-		rw.WriteHeader(http.StatusInternalServerError)
+	if writer := newEventWriter(rw, req); writer != nil {
+		solveStream(ctx, writer, req, request)
 		return
 	}
 
-	_, err = rw.Write(resBs)
+	response, err := solve(ctx, request)
 	if err != nil {
-		log.Err(err).Msg("failed to write response")
-		rw.WriteHeader(http.StatusInternalServerError)
+		status, apiErr := solveError(requestID(req), err)
+		logger.Err(err).Str("code", apiErr.Code).Msg("failed to solve")
+		span.SetAttributes(semconv.HTTPStatusCode(status))
+		span.SetStatus(codes.Error, err.Error())
+		response.Error = apiErr
+		writeJSON(rw, status, response)
 		return
 	}
+
+	span.SetAttributes(semconv.HTTPStatusCode(http.StatusOK))
+	writeJSON(rw, http.StatusOK, response)
 }
 
 // solve converts the request to a grid which can be solved
-// by the sudoku solver.
+// by the sudoku solver. The response is returned even if there
+// is an error, containing any solutions found before the error.
+// Results are returned from the cache of the request, if any,
+// unless the request bypasses it.
 func solve(ctx context.Context, request *api.SolveRequest) (*api.SolveResponse, error) {
-	grid := sudoku.Grid{}
-	for r, row := range request.Grid {
+	// Requests bypassing the cache neither read nor write it
+	solveCache := cache.FromContext(ctx)
+	if request.NoCache {
+		solveCache = nil
+	}
+
+	var keys cacheKeys
+	if solveCache != nil {
+		keys = newCacheKeys(request)
+		result, ok := keys.get(solveCache)
+		metrics.CacheLookup(ok)
+		trace.SpanFromContext(ctx).SetAttributes(attribute.Bool("sudoku.cached", ok))
+		if ok {
+			response := result.response
+			response.Cached = true
+			return &response, result.err
+		}
+	}
+
+	response := &api.SolveResponse{
+		Solutions: make([]api.Grid, 0),
+	}
+
+	summary, err := stream(ctx, request, func(solution api.Grid) bool {
+		response.Solutions = append(response.Solutions, solution)
+		return true
+	})
+
+	response.Completed = completed(summary, err)
+	response.StoppedBy = string(summary.Limit)
+	response.Stats = fromStats(summary.Stats, request.Stats)
+	if solveCache != nil && answered(err) {
+		keys.put(solveCache, cachedResult{response: *response, err: err})
+	}
+
+	return response, err
+}
+
+// completed returns true if the search of a solve found every
+// solution. A solve which failed before its search started, e.g. as the
+// server is overloaded or the grid is invalid, has an empty summary but
+// is not complete.
+func completed(summary sudoku.Summary, err error) bool {
+	return summary.Completed() && answered(err)
+}
+
+// answered returns true if the error of a solve is a definite answer of
+// its search, i.e. there is no error, no solution or the grid cannot be
+// solved without guessing. Only such results are cached, as any other
+// error, e.g. a timeout, an invalid grid or an overloaded server, may
+// not recur or is detected without searching.
+func answered(err error) bool {
+	return err == nil || errors.Is(err, sudoku.ErrNoSolution) || errors.Is(err, sudoku.ErrGuessRequired)
+}
+
+// cachedResult is the result of a solve held by the cache, whose
+// solutions are those of the grid of its key.
+type cachedResult struct {
+	response api.SolveResponse
+	err      error
+}
+
+// cacheKeys are the keys of the result of a request in the cache. A
+// result which does not depend on the order the grid is searched in,
+// i.e. a complete result with at most one solution, is cached under
+// the key of the canonical form of the grid, so that equivalent grids
+// share it. Other results are cached under the key of the grid as
+// given, keeping the solutions and their order those of its own search.
+type cacheKeys struct {
+	given string
+
+	// canonical is the key of the canonical form of the grid, which
+	// the transform maps the grid onto, or empty if the grid is not
+	// canonicalized.
+	canonical string
+	transform sudoku.Transform
+}
+
+// newCacheKeys returns the keys of the result of the request. The grid
+// is not canonicalized if the search is limited by its nodes or depth,
+// which depend on the layout of the grid.
+func newCacheKeys(request *api.SolveRequest) cacheKeys {
+	keys := cacheKeys{given: cacheKey(request, request.Grid)}
+	if request.MaxNodes == 0 && request.MaxDepth == 0 {
+		if converted, err := toGrid(request.Grid); err == nil {
+			var canonical sudoku.Grid
+			canonical, keys.transform = sudoku.Canonicalize(converted)
+			keys.canonical = cacheKey(request, fromGrid(canonical))
+		}
+	}
+
+	return keys
+}
+
+// get returns the cached result of the request, if any, with the
+// solutions of its grid.
+func (k cacheKeys) get(c *cache.Cache) (cachedResult, bool) {
+	if k.canonical != "" {
+		if cached, ok := c.Get(k.canonical); ok {
+			result := cached.(cachedResult)
+			result.response.Solutions = transformGrids(result.response.Solutions, k.transform.Inverse())
+			return result, true
+		}
+	}
+
+	cached, ok := c.Get(k.given)
+	if !ok {
+		return cachedResult{}, false
+	}
+
+	result := cached.(cachedResult)
+	result.response.Solutions = append(make([]api.Grid, 0, len(result.response.Solutions)), result.response.Solutions...)
+	return result, true
+}
+
+// put caches the result of the request.
+func (k cacheKeys) put(c *cache.Cache, result cachedResult) {
+	if k.canonical != "" && result.response.Completed && len(result.response.Solutions) <= 1 {
+		result.response.Solutions = transformGrids(result.response.Solutions, k.transform)
+		c.Put(k.canonical, result)
+		return
+	}
+
+	result.response.Solutions = append(make([]api.Grid, 0, len(result.response.Solutions)), result.response.Solutions...)
+	c.Put(k.given, result)
+}
+
+// cacheKey returns the key of the result of the request for the grid,
+// which is a hash of the grid, the rules and every field of the request
+// which changes the result. Requests differing only in their timeout
+// share a key.
+func cacheKey(request *api.SolveRequest, grid api.Grid) string {
+	var b strings.Builder
+	b.WriteString("rules=standard;grid=")
+	for _, row := range grid {
+		for _, entry := range row {
+			b.WriteByte(byte('0' + entry))
+		}
+	}
+
+	// The order of the disabled techniques does not matter
+	disabled := append([]string(nil), request.DisabledTechniques...)
+	sort.Strings(disabled)
+
+	fmt.Fprintf(&b, ";techniques=%s;disabled=%s;logic_only=%t;max_solutions=%d;max_nodes=%d;max_depth=%d;stats=%t",
+		strings.Join(request.Techniques, ","), strings.Join(disabled, ","), request.LogicOnly,
+		request.MaxSolutions, request.MaxNodes, request.MaxDepth, request.Stats)
+
+	sum := sha256.Sum256([]byte(b.String()))
+	return hex.EncodeToString(sum[:])
+}
+
+// transformGrids returns the completed grids mapped by the transform.
+func transformGrids(grids []api.Grid, transform sudoku.Transform) []api.Grid {
+	transformed := make([]api.Grid, 0, len(grids))
+	for _, grid := range grids {
+		converted, err := toGrid(grid)
+		if err != nil {
+			continue
+		}
+		transformed = append(transformed, fromGrid(transform.Apply(converted)))
+	}
+
+	return transformed
+}
+
+// stream converts the request to a grid and calls yield with each
+// solution found by the sudoku solver, configured by the request and
+// then by the extra options.
+func stream(ctx context.Context, request *api.SolveRequest, yield func(solution api.Grid) bool, extra ...sudoku.Option) (sudoku.Summary, error) {
+	grid, err := toGrid(request.Grid)
+	if err != nil {
+		return sudoku.Summary{}, err
+	}
+
+	options, err := solveOptions(request)
+	if err != nil {
+		return sudoku.Summary{}, err
+	}
+	options = append(options, extra...)
+
+	release, err := acquireSolve(ctx)
+	if err != nil {
+		return sudoku.Summary{}, err
+	}
+	defer release()
+
+	ctx, settle, err := auth.Meter(ctx)
+	if err != nil {
+		return sudoku.Summary{}, err
+	}
+
+	done := metrics.SolveStarted()
+	count := 0
+	summary, err := sudoku.StandardStream(ctx, grid, func(solution sudoku.Grid) bool {
+		count++
+		return yield(fromGrid(solution))
+	}, options...)
+	err = settle(err)
+	done(count, summary.Stats, err)
+
+	trace.SpanFromContext(ctx).SetAttributes(
+		attribute.String("sudoku.outcome", metrics.Outcome(err)),
+		attribute.Int("sudoku.solutions", count),
+		attribute.Int("sudoku.nodes", summary.Stats.Nodes),
+		attribute.Int("sudoku.guesses", summary.Stats.Guesses),
+	)
+	if errors.Is(err, sudoku.ErrInvalidGrid) {
+		if cell := conflictingCell(request.Grid); cell != nil {
+			err = &fieldError{field: "grid", cell: cell, err: err}
+		}
+	}
+
+	return summary, err
+}
+
+// acquireSolve waits for a slot of the concurrency limit of the server
+// for a single solve, see middleware.AcquireSolve. A context which is
+// done while queued fails with the error of the solver.
+func acquireSolve(ctx context.Context) (func(), error) {
+	release, err := middleware.AcquireSolve(ctx)
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return nil, sudoku.ErrTimeout
+	case errors.Is(err, context.Canceled):
+		return nil, sudoku.ErrCancelled
+	}
+
+	return release, err
+}
+
+// conflictingCell returns the first cell whose value is repeated
+// earlier in its row, column or square.
+func conflictingCell(grid api.Grid) *api.Cell {
+	var rows, columns, squares [sudoku.Size][sudoku.Size + 1]bool
+	for r, row := range grid {
+		for c, value := range row {
+			if value == 0 {
+				continue
+			}
+
+			s := 3*(r/3) + c/3
+			if rows[r][value] || columns[c][value] || squares[s][value] {
+				return &api.Cell{Row: r, Column: c}
+			}
+			rows[r][value], columns[c][value], squares[s][value] = true, true, true
+		}
+	}
+
+	return nil
+}
+
+// fromStats converts the statistics of the solver into their api
+// representation, including the detailed statistics if requested.
+func fromStats(stats sudoku.Stats, detailed bool) *api.SolveStats {
+	converted := &api.SolveStats{
+		Nodes:    stats.Nodes,
+		MaxDepth: stats.MaxDepth,
+	}
+	if !detailed {
+		return converted
+	}
+
+	converted.Guesses = stats.Guesses
+	converted.Deductions = make(map[string]int, len(stats.Deductions))
+	for technique, count := range stats.Deductions {
+		converted.Deductions[string(technique)] = count
+	}
+
+	// The deduction time is summed over every goroutine so the search
+	// time is only an estimate when searching concurrently
+	search := stats.Duration - stats.DeductionTime
+	if search < 0 {
+		search = 0
+	}
+
+	converted.DurationMs = milliseconds(stats.Duration)
+	converted.DeductionMs = milliseconds(stats.DeductionTime)
+	converted.SearchMs = milliseconds(search)
+	return converted
+}
+
+// milliseconds converts the duration into fractional milliseconds.
+func milliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+// clueCount returns the number of filled cells of the grid.
+func clueCount(grid api.Grid) int {
+	count := 0
+	for _, row := range grid {
+		for _, entry := range row {
+			if entry > 0 {
+				count++
+			}
+		}
+	}
+
+	return count
+}
+
+// toGrid converts a validated api grid into a sudoku grid.
+func toGrid(grid api.Grid) (sudoku.Grid, error) {
+	converted := sudoku.Grid{}
+	for r, row := range grid {
 		for c, entry := range row {
 			if entry > 0 {
-				err := grid.Set(r, c, entry)
+				err := converted.Set(r, c, entry)
 				if err != nil {
-					return nil, err
+					return sudoku.Grid{}, err
 				}
 			}
 		}
 	}
 
-	solutions := sudoku.StandardSolve(ctx, grid)
+	return converted, nil
+}
 
-	response := &api.SolveResponse{
-		Completed: ctx.Err() == nil,
-		Solutions: make([]api.Grid, len(solutions)),
-	}
-	for i, s := range solutions {
-		response.Solutions[i] = make([][]int, sudoku.Size)
-		for row := 0; row < sudoku.Size; row++ {
-			response.Solutions[i][row] = make([]int, sudoku.Size)
-			for column := 0; column < sudoku.Size; column++ {
-				response.Solutions[i][row][column], _ = s.Get(row, column)
-			}
+// fromGrid converts a sudoku grid into its api representation.
+func fromGrid(grid sudoku.Grid) api.Grid {
+	converted := make(api.Grid, sudoku.Size)
+	for row := 0; row < sudoku.Size; row++ {
+		converted[row] = make([]int, sudoku.Size)
+		for column := 0; column < sudoku.Size; column++ {
+			converted[row][column], _ = grid.Get(row, column)
+		}
+	}
+
+	return converted
+}
+
+// solveOptions converts the strategy fields of the request into
+// options for the sudoku solver.
+func solveOptions(request *api.SolveRequest) ([]sudoku.Option, error) {
+	var options []sudoku.Option
+
+	if len(request.Techniques) > 0 {
+		techniques, err := parseTechniques("techniques", request.Techniques)
+		if err != nil {
+			return nil, err
+		}
+		options = append(options, sudoku.WithTechniques(techniques...))
+	}
+
+	if len(request.DisabledTechniques) > 0 {
+		techniques, err := parseTechniques("disabled_techniques", request.DisabledTechniques)
+		if err != nil {
+			return nil, err
 		}
+		options = append(options, sudoku.WithoutTechniques(techniques...))
 	}
 
-	return response, nil
+	if request.LogicOnly {
+		options = append(options, sudoku.WithoutGuessing())
+	}
+
+	options = append(options,
+		sudoku.WithMaxSolutions(request.MaxSolutions),
+		sudoku.WithMaxNodes(request.MaxNodes),
+		sudoku.WithMaxDepth(request.MaxDepth),
+	)
+
+	return options, nil
+}
+
+// parseTechniques converts the names into techniques.
+func parseTechniques(field string, names []string) ([]sudoku.Technique, error) {
+	techniques := make([]sudoku.Technique, len(names))
+	for i, name := range names {
+		technique, err := sudoku.ParseTechnique(name)
+		if err != nil {
+			return nil, &fieldError{field: field, err: fmt.Errorf("invalid technique %q: %w", name, err)}
+		}
+		techniques[i] = technique
+	}
+
+	return techniques, nil
 }
 
 // validateSolveRequest validates a solve request.
 func validateSolveRequest(request *api.SolveRequest) error {
-	if len(request.Grid) != sudoku.Size {
-		return fmt.Errorf("invalid number of rows %d (expected %d)", len(request.Grid), sudoku.Size)
+	err := validateGrid(request.Grid)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case request.MaxSolutions < 0:
+		return newFieldError("max_solutions", "invalid max_solutions %d", request.MaxSolutions)
+	case request.MaxNodes < 0:
+		return newFieldError("max_nodes", "invalid max_nodes %d", request.MaxNodes)
+	case request.MaxDepth < 0:
+		return newFieldError("max_depth", "invalid max_depth %d", request.MaxDepth)
+	}
+
+	_, err = solveOptions(request)
+	return err
+}
+
+// validateGrid validates the dimensions and entries of a grid.
+func validateGrid(grid api.Grid) error {
+	if len(grid) != sudoku.Size {
+		return newFieldError("grid", "invalid number of rows %d (expected %d)", len(grid), sudoku.Size)
 	}
 
-	for r, row := range request.Grid {
+	for r, row := range grid {
 		if len(row) != sudoku.Size {
-			return fmt.Errorf("row %d has invalid number of columns %d (expected %d)", r, len(row), sudoku.Size)
+			return newFieldError("grid", "row %d has invalid number of columns %d (expected %d)", r, len(row), sudoku.Size)
 		}
 
 		for c, entry := range row {
 			if entry < 0 || entry > sudoku.Size {
-				return fmt.Errorf("invalid entry: %d at position (%d, %d) ", entry, r, c)
+				return newCellError(r, c, "invalid entry: %d at position (%d, %d) ", entry, r, c)
 			}
 		}
 	}
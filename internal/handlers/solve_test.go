@@ -0,0 +1,351 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/PeterEFinch/sudoku-solver/api"
+	"github.com/PeterEFinch/sudoku-solver/internal/cache"
+	"github.com/PeterEFinch/sudoku-solver/internal/metrics"
+	"github.com/PeterEFinch/sudoku-solver/internal/middleware"
+	"github.com/PeterEFinch/sudoku-solver/internal/tracing"
+)
+
+const difficultGrid = `[
+	[8, 0, 0, 0, 0, 0, 0, 0, 0],
+	[0, 0, 3, 6, 0, 0, 0, 0, 0],
+	[0, 7, 0, 0, 9, 0, 2, 0, 0],
+	[0, 5, 0, 0, 0, 7, 0, 0, 0],
+	[0, 0, 0, 0, 4, 5, 7, 0, 0],
+	[0, 0, 0, 1, 0, 0, 0, 3, 0],
+	[0, 0, 1, 0, 0, 0, 0, 6, 8],
+	[0, 0, 8, 5, 0, 0, 0, 1, 0],
+	[0, 9, 0, 0, 0, 0, 4, 0, 0]
+]`
+
+const invalidGrid = `[
+	[8, 8, 0, 0, 0, 0, 0, 0, 0],
+	[0, 0, 0, 0, 0, 0, 0, 0, 0],
+	[0, 0, 0, 0, 0, 0, 0, 0, 0],
+	[0, 0, 0, 0, 0, 0, 0, 0, 0],
+	[0, 0, 0, 0, 0, 0, 0, 0, 0],
+	[0, 0, 0, 0, 0, 0, 0, 0, 0],
+	[0, 0, 0, 0, 0, 0, 0, 0, 0],
+	[0, 0, 0, 0, 0, 0, 0, 0, 0],
+	[0, 0, 0, 0, 0, 0, 0, 0, 0]
+]`
+
+const unsolvableGrid = `[
+	[1, 2, 3, 4, 5, 6, 7, 8, 0],
+	[0, 0, 0, 0, 0, 0, 0, 0, 9],
+	[0, 0, 0, 0, 0, 0, 0, 0, 0],
+	[0, 0, 0, 0, 0, 0, 0, 0, 0],
+	[0, 0, 0, 0, 0, 0, 0, 0, 0],
+	[0, 0, 0, 0, 0, 0, 0, 0, 0],
+	[0, 0, 0, 0, 0, 0, 0, 0, 0],
+	[0, 0, 0, 0, 0, 0, 0, 0, 0],
+	[0, 0, 0, 0, 0, 0, 0, 0, 0]
+]`
+
+func TestSolve(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		body   string
+		status int
+		code   string
+		cell   *api.Cell
+	}{
+		{"solved", http.MethodPost, `{"timeout_ms": 5000, "grid": ` + difficultGrid + `}`, http.StatusOK, "", nil},
+		{"malformed request", http.MethodPost, `{"grid": `, http.StatusBadRequest, api.ErrorCodeMalformedRequest, nil},
+		{"invalid request", http.MethodPost, `{"timeout_ms": 5000, "grid": [[1]]}`, http.StatusBadRequest, api.ErrorCodeInvalidRequest, nil},
+		{"invalid grid", http.MethodPost, `{"timeout_ms": 5000, "grid": ` + invalidGrid + `}`, http.StatusBadRequest, api.ErrorCodeInvalidGrid, &api.Cell{Row: 0, Column: 1}},
+		{"invalid entry", http.MethodPost, `{"timeout_ms": 5000, "grid": ` + strings.Replace(invalidGrid, "8, 8", "8, 10", 1) + `}`, http.StatusBadRequest, api.ErrorCodeInvalidRequest, &api.Cell{Row: 0, Column: 1}},
+		{"method not allowed", http.MethodGet, ``, http.StatusMethodNotAllowed, api.ErrorCodeMethodNotAllowed, nil},
+		{"no solution", http.MethodPost, `{"timeout_ms": 5000, "grid": ` + unsolvableGrid + `}`, http.StatusUnprocessableEntity, api.ErrorCodeNoSolution, nil},
+		{"guess required", http.MethodPost, `{"timeout_ms": 5000, "logic_only": true, "grid": ` + difficultGrid + `}`, http.StatusConflict, api.ErrorCodeGuessRequired, nil},
+		{"timeout", http.MethodPost, `{"timeout_ms": 1, "grid": ` + strings.Replace(invalidGrid, "8, 8", "0, 0", 1) + `}`, http.StatusGatewayTimeout, api.ErrorCodeTimeout, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/solve", strings.NewReader(tt.body))
+			rw := httptest.NewRecorder()
+
+			Solve(rw, req)
+
+			if rw.Code != tt.status {
+				t.Fatalf("expected status %d, got %d: %s", tt.status, rw.Code, rw.Body.String())
+			}
+
+			response := &api.SolveResponse{}
+			err := json.Unmarshal(rw.Body.Bytes(), response)
+			if err != nil {
+				t.Fatalf("failed to unmarshal response: %v", err)
+			}
+
+			code := ""
+			var cell *api.Cell
+			if response.Error != nil {
+				code = response.Error.Code
+				cell = response.Error.Cell
+			}
+
+			if code != tt.code {
+				t.Fatalf("expected error code %q, got %q", tt.code, code)
+			}
+
+			if (cell == nil) != (tt.cell == nil) || cell != nil && *cell != *tt.cell {
+				t.Fatalf("expected error cell %v, got %v", tt.cell, cell)
+			}
+		})
+	}
+}
+
+func TestSolve_Tracing(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := tracing.Install(sdktrace.NewSimpleSpanProcessor(exporter))
+	defer provider.Shutdown(context.Background())
+
+	req := httptest.NewRequest(http.MethodPost, "/solve", strings.NewReader(`{"timeout_ms": 5000, "grid": `+difficultGrid+`}`))
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	Solve(httptest.NewRecorder(), req)
+
+	spans := make(map[string]tracetest.SpanStub)
+	for _, span := range exporter.GetSpans() {
+		spans[span.Name] = span
+	}
+
+	root, ok := spans["POST /solve"]
+	if !ok {
+		t.Fatalf("expected a span for the request, got %+v", spans)
+	}
+
+	if root.SpanContext.TraceID().String() != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Fatalf("expected the trace of the client to be continued, got trace %s", root.SpanContext.TraceID())
+	}
+
+	for _, name := range []string{"validate", "sudoku.deduction", "sudoku.search"} {
+		span, ok := spans[name]
+		if !ok || span.Parent.SpanID() != root.SpanContext.SpanID() {
+			t.Fatalf("expected span %q to be a child of the request span, got %+v", name, span)
+		}
+	}
+
+	attributes := make(map[attribute.Key]attribute.Value)
+	for _, kv := range root.Attributes {
+		attributes[kv.Key] = kv.Value
+	}
+
+	if outcome := attributes["sudoku.outcome"].AsString(); outcome != metrics.OutcomeSolved {
+		t.Fatalf("expected outcome %q, got %q", metrics.OutcomeSolved, outcome)
+	}
+
+	if clues := attributes["sudoku.clues"].AsInt64(); clues != 21 {
+		t.Fatalf("expected 21 clues, got %d", clues)
+	}
+}
+
+func TestSolve_Cache(t *testing.T) {
+	handler := cache.New(10, 0).Middleware(http.HandlerFunc(Solve))
+	timeoutGrid := strings.Replace(invalidGrid, "8, 8", "0, 0", 1)
+
+	tests := []struct {
+		name    string
+		grid    string
+		options string
+		header  string
+		status  int
+		cached  bool
+	}{
+		{"miss", difficultGrid, `"timeout_ms": 5000`, "", http.StatusOK, false},
+		{"hit", difficultGrid, `"timeout_ms": 5000`, "", http.StatusOK, true},
+		{"hit ignoring timeout", difficultGrid, `"timeout_ms": 4000`, "", http.StatusOK, true},
+		{"hit equivalent grid", transposedGrid(t, difficultGrid), `"timeout_ms": 5000`, "", http.StatusOK, true},
+		{"bypassed by body", difficultGrid, `"timeout_ms": 5000, "no_cache": true`, "", http.StatusOK, false},
+		{"bypassed by header", difficultGrid, `"timeout_ms": 5000`, "no-cache", http.StatusOK, false},
+		{"different options", difficultGrid, `"timeout_ms": 5000, "max_solutions": 1`, "", http.StatusOK, false},
+		{"error miss", unsolvableGrid, `"timeout_ms": 5000`, "", http.StatusUnprocessableEntity, false},
+		{"error hit", unsolvableGrid, `"timeout_ms": 5000`, "", http.StatusUnprocessableEntity, true},
+		{"invalid grid miss", invalidGrid, `"timeout_ms": 5000`, "", http.StatusBadRequest, false},
+		{"invalid grid not cached", invalidGrid, `"timeout_ms": 5000`, "", http.StatusBadRequest, false},
+		{"timeout miss", timeoutGrid, `"timeout_ms": 1`, "", http.StatusGatewayTimeout, false},
+		{"timeout not cached", timeoutGrid, `"timeout_ms": 1`, "", http.StatusGatewayTimeout, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body := `{` + tt.options + `, "grid": ` + tt.grid + `}`
+			req := httptest.NewRequest(http.MethodPost, "/solve", strings.NewReader(body))
+			if tt.header != "" {
+				req.Header.Set("Cache-Control", tt.header)
+			}
+			rw := httptest.NewRecorder()
+
+			handler.ServeHTTP(rw, req)
+
+			if rw.Code != tt.status {
+				t.Fatalf("expected status %d, got %d: %s", tt.status, rw.Code, rw.Body.String())
+			}
+
+			response := &api.SolveResponse{}
+			if err := json.Unmarshal(rw.Body.Bytes(), response); err != nil {
+				t.Fatalf("failed to unmarshal response: %v", err)
+			}
+
+			if response.Cached != tt.cached {
+				t.Fatalf("expected cached to be %t, got %t", tt.cached, response.Cached)
+			}
+
+			if tt.status != http.StatusOK {
+				return
+			}
+
+			if len(response.Solutions) != 1 {
+				t.Fatalf("expected 1 solution, got %d", len(response.Solutions))
+			}
+
+			var grid api.Grid
+			_ = json.Unmarshal([]byte(tt.grid), &grid)
+			for r, row := range grid {
+				for c, entry := range row {
+					if entry != 0 && response.Solutions[0][r][c] != entry {
+						t.Fatalf("expected the solution to keep the clue %d at (%d, %d), got %v", entry, r, c, response.Solutions[0])
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestSolve_CacheOverloaded(t *testing.T) {
+	limiter := middleware.NewConcurrencyLimiter(1, 0)
+	solveCache := cache.New(10, 0)
+	handler := limiter.Share(solveCache.Middleware(http.HandlerFunc(Solve)))
+	solve := func(grid, options string) *httptest.ResponseRecorder {
+		body := `{"timeout_ms": 5000` + options + `, "grid": ` + grid + `}`
+		rw := httptest.NewRecorder()
+		handler.ServeHTTP(rw, httptest.NewRequest(http.MethodPost, "/solve", strings.NewReader(body)))
+		return rw
+	}
+
+	if rw := solve(difficultGrid, ""); rw.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, rw.Code, rw.Body.String())
+	}
+
+	// Cache hits do not need a solve slot, unlike the solves
+	release, err := limiter.Acquire(context.Background())
+	if err != nil {
+		t.Fatalf("failed to acquire slot: %v", err)
+	}
+
+	if rw := solve(difficultGrid, ""); rw.Code != http.StatusOK || !strings.Contains(rw.Body.String(), `"cached":true`) {
+		t.Fatalf("expected a cached response while overloaded, got %d: %s", rw.Code, rw.Body.String())
+	}
+
+	rw := solve(unsolvableGrid, "")
+	if rw.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected status %d for a solve while overloaded, got %d: %s", http.StatusServiceUnavailable, rw.Code, rw.Body.String())
+	}
+
+	// The search never started, so it did not find every solution
+	response := &api.SolveResponse{}
+	if err := json.Unmarshal(rw.Body.Bytes(), response); err != nil || response.Completed {
+		t.Fatalf("expected a response which is not completed, got %s: %v", rw.Body.String(), err)
+	}
+	release()
+
+	// Neither the overloaded solve nor one bypassing the cache is cached
+	if rw := solve(unsolvableGrid, `, "no_cache": true`); rw.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("expected status %d once a slot is free, got %d: %s", http.StatusUnprocessableEntity, rw.Code, rw.Body.String())
+	}
+	if solveCache.Len() != 1 {
+		t.Fatalf("expected only the first result to be cached, got %d results", solveCache.Len())
+	}
+
+	if rw := solve(unsolvableGrid, ""); rw.Code != http.StatusUnprocessableEntity || strings.Contains(rw.Body.String(), `"cached":true`) {
+		t.Fatalf("expected the grid to be solved rather than cached, got %d: %s", rw.Code, rw.Body.String())
+	}
+}
+
+func TestSolve_CacheSearchOrder(t *testing.T) {
+	handler := cache.New(10, 0).Middleware(http.HandlerFunc(Solve))
+	solve := func(grid, options string) *api.SolveResponse {
+		body := `{"timeout_ms": 5000` + options + `, "grid": ` + grid + `}`
+		rw := httptest.NewRecorder()
+		handler.ServeHTTP(rw, httptest.NewRequest(http.MethodPost, "/solve", strings.NewReader(body)))
+		if rw.Code != http.StatusOK {
+			t.Fatalf("expected status %d, got %d: %s", http.StatusOK, rw.Code, rw.Body.String())
+		}
+
+		response := &api.SolveResponse{}
+		if err := json.Unmarshal(rw.Body.Bytes(), response); err != nil {
+			t.Fatalf("failed to unmarshal response: %v", err)
+		}
+
+		return response
+	}
+
+	// Results whose solutions depend on the order of the search are not
+	// shared by equivalent grids
+	multiple := strings.Replace(difficultGrid, "[8, 0", "[0, 0", 1)
+	tests := []struct {
+		name    string
+		grid    string
+		options string
+	}{
+		{"multiple solutions", multiple, ""},
+		{"max solutions", difficultGrid, `, "max_solutions": 1`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if response := solve(tt.grid, tt.options); response.Cached {
+				t.Fatalf("expected the first solve to miss the cache")
+			}
+
+			transposed := transposedGrid(t, tt.grid)
+			uncached := solve(transposed, tt.options)
+			if uncached.Cached {
+				t.Fatalf("expected the equivalent grid to miss the cache")
+			}
+
+			cached := solve(transposed, tt.options)
+			if !cached.Cached || !reflect.DeepEqual(cached.Solutions, uncached.Solutions) {
+				t.Fatalf("expected the cached solutions to be those of the grid in the same order")
+			}
+		})
+	}
+
+	if solutions := len(solve(multiple, "").Solutions); solutions < 2 {
+		t.Fatalf("expected the grid to have multiple solutions, got %d", solutions)
+	}
+}
+
+// transposedGrid returns the JSON grid with its rows and columns swapped.
+func transposedGrid(t *testing.T, grid string) string {
+	var rows api.Grid
+	if err := json.Unmarshal([]byte(grid), &rows); err != nil {
+		t.Fatalf("failed to unmarshal grid: %v", err)
+	}
+
+	transposed := make(api.Grid, len(rows))
+	for c := range transposed {
+		transposed[c] = make([]int, len(rows))
+		for r := range rows {
+			transposed[c][r] = rows[r][c]
+		}
+	}
+
+	bs, _ := json.Marshal(transposed)
+	return string(bs)
+}
@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+
+	"github.com/PeterEFinch/sudoku-solver/api"
+	"github.com/PeterEFinch/sudoku-solver/internal/tracing"
+)
+
+const (
+	contentTypeNDJSON      = "application/x-ndjson"
+	contentTypeEventStream = "text/event-stream"
+)
+
+// eventWriter writes the events of a streamed response, flushing
+// each event to the client as soon as it is written.
+type eventWriter interface {
+	write(event string, value interface{}) error
+}
+
+// newEventWriter returns a writer for the streaming format accepted by
+// the request or nil if the request does not accept a streamed response.
+func newEventWriter(rw http.ResponseWriter, req *http.Request) eventWriter {
+	mediaType, _, _ := mime.ParseMediaType(req.Header.Get("Accept"))
+	if mediaType != contentTypeNDJSON && mediaType != contentTypeEventStream {
+		return nil
+	}
+
+	flusher, ok := rw.(http.Flusher)
+	if !ok {
+		return nil
+	}
+
+	rw.Header().Set("Content-Type", mediaType)
+	rw.Header().Set("Cache-Control", "no-cache")
+	if mediaType == contentTypeEventStream {
+		return &sseWriter{rw: rw, flusher: flusher}
+	}
+
+	return &ndjsonWriter{rw: rw, flusher: flusher}
+}
+
+// solveStream writes each solution as a separate event followed by a
+// final event summarising the search.
+func solveStream(ctx context.Context, writer eventWriter, req *http.Request, request *api.SolveRequest) {
+	logger := tracing.Logger(ctx)
+	summary := &api.SolveSummary{}
+	result, err := stream(ctx, request, func(solution api.Grid) bool {
+		summary.Count++
+		err := writer.write(api.EventSolution, api.SolveEvent{Solution: solution})
+		if err != nil {
+			logger.Err(err).Msg("failed to write solution")
+			return false
+		}
+
+		return true
+	})
+
+	summary.Completed = completed(result, err)
+	summary.StoppedBy = string(result.Limit)
+	summary.Stats = fromStats(result.Stats, request.Stats)
+
+	event, value := api.EventDone, api.SolveEvent{Summary: summary}
+	if err != nil {
+		_, apiErr := solveError(requestID(req), err)
+		logger.Err(err).Str("code", apiErr.Code).Msg("failed to solve")
+		event, value.Error = api.EventError, apiErr
+	}
+
+	err = writer.write(event, value)
+	if err != nil {
+		logger.Err(err).Msg("failed to write summary")
+	}
+}
+
+// ndjsonWriter writes events as newline delimited JSON.
+type ndjsonWriter struct {
+	rw      http.ResponseWriter
+	flusher http.Flusher
+}
+
+func (w *ndjsonWriter) write(_ string, value interface{}) error {
+	bs, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	_, err = w.rw.Write(append(bs, '\n'))
+	if err != nil {
+		return err
+	}
+
+	w.flusher.Flush()
+	return nil
+}
+
+// sseWriter writes events as server-sent events.
+type sseWriter struct {
+	rw      http.ResponseWriter
+	flusher http.Flusher
+}
+
+func (w *sseWriter) write(event string, value interface{}) error {
+	bs, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(w.rw, "event: %s\ndata: %s\n\n", event, bs)
+	if err != nil {
+		return err
+	}
+
+	w.flusher.Flush()
+	return nil
+}
@@ -0,0 +1,12 @@
+package jobs
+
+const (
+	ErrNotFound Error = "job_not_found"
+	ErrClosed   Error = "manager_closed"
+)
+
+type Error string
+
+func (e Error) Error() string {
+	return string(e)
+}
@@ -0,0 +1,343 @@
+// Package jobs runs solves asynchronously, allowing clients to poll
+// for their progress rather than holding a connection open.
+package jobs
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/PeterEFinch/sudoku-solver/api"
+)
+
+const (
+	defaultRetention    = time.Hour
+	defaultSaveInterval = 250 * time.Millisecond
+	defaultTimeout      = time.Minute
+	defaultMaxSolutions = 1000
+)
+
+// Runner solves the request, calling yield with each solution as it
+// is found and report with the number of nodes explored so far as the
+// search progresses, which may be concurrent with yield. It returns the
+// response summarising the solve, without the solutions, with its Error
+// set if the solve failed.
+type Runner func(ctx context.Context, request *api.SolveRequest, yield func(solution api.Grid), report func(nodes int)) api.SolveResponse
+
+// Option configures a Manager.
+type Option func(manager *Manager)
+
+// WithRetention sets how long finished jobs are kept before they are
+// deleted from the store.
+func WithRetention(retention time.Duration) Option {
+	return func(manager *Manager) {
+		manager.retention = retention
+	}
+}
+
+// WithSaveInterval sets the minimum interval between saving the
+// partial solutions of a running job.
+func WithSaveInterval(interval time.Duration) Option {
+	return func(manager *Manager) {
+		manager.saveInterval = interval
+	}
+}
+
+// WithTimeouts sets the timeout of jobs without a timeout_ms and caps
+// the timeout of every job, where a max of zero means no cap.
+func WithTimeouts(timeout, max time.Duration) Option {
+	return func(manager *Manager) {
+		manager.timeout = timeout
+		manager.maxTimeout = max
+	}
+}
+
+// WithMaxSolutions caps the number of solutions each job stores,
+// stopping the solve once it is reached.
+func WithMaxSolutions(n int) Option {
+	return func(manager *Manager) {
+		manager.maxSolutions = n
+	}
+}
+
+// Manager runs jobs in the background and records their progress in
+// a store.
+type Manager struct {
+	store        Store
+	run          Runner
+	retention    time.Duration
+	saveInterval time.Duration
+	timeout      time.Duration
+	maxTimeout   time.Duration
+	maxSolutions int
+
+	mu       sync.Mutex
+	closed   bool
+	running  map[string]*execution
+	finished []finishedJob
+	wg       sync.WaitGroup
+}
+
+// execution tracks a running job.
+type execution struct {
+	cancel    context.CancelFunc
+	cancelled bool
+	done      chan struct{}
+}
+
+// finishedJob records when a job finished so it can be deleted once
+// the retention has passed.
+type finishedJob struct {
+	id         string
+	finishedAt time.Time
+}
+
+// NewManager returns a manager running jobs with the runner and
+// storing them in the store.
+func NewManager(store Store, run Runner, options ...Option) *Manager {
+	manager := &Manager{
+		store:        store,
+		run:          run,
+		retention:    defaultRetention,
+		saveInterval: defaultSaveInterval,
+		timeout:      defaultTimeout,
+		maxSolutions: defaultMaxSolutions,
+		running:      make(map[string]*execution),
+	}
+
+	for _, option := range options {
+		option(manager)
+	}
+
+	return manager
+}
+
+// Create starts a job solving the request. Jobs without a timeout_ms
+// use the timeout of the manager, and the timeout and solutions of
+// every job are capped by the limits of the manager.
+func (m *Manager) Create(ctx context.Context, request api.SolveRequest) (api.Job, error) {
+	m.prune(ctx)
+
+	now := time.Now().UTC()
+	job := api.Job{
+		ID:     newID(),
+		Status: api.JobStatusPending,
+		Result: api.SolveResponse{
+			Solutions: make([]api.Grid, 0),
+		},
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.closed {
+		return api.Job{}, ErrClosed
+	}
+
+	err := m.store.Save(ctx, job)
+	if err != nil {
+		return api.Job{}, err
+	}
+
+	if m.maxSolutions > 0 && (request.MaxSolutions == 0 || request.MaxSolutions > m.maxSolutions) {
+		request.MaxSolutions = m.maxSolutions
+	}
+
+	// Jobs outlive the request which created them but keep its values,
+	// e.g. the API key the solve is charged to
+	var jobCtx context.Context
+	var cancel context.CancelFunc
+	if timeout := m.jobTimeout(request); timeout > 0 {
+		jobCtx, cancel = context.WithTimeout(detached{ctx}, timeout)
+	} else {
+		jobCtx, cancel = context.WithCancel(detached{ctx})
+	}
+
+	exec := &execution{cancel: cancel, done: make(chan struct{})}
+	m.running[job.ID] = exec
+	m.wg.Add(1)
+	go m.execute(jobCtx, exec, job, request)
+
+	return job, nil
+}
+
+// jobTimeout returns the timeout of the request, if any.
+func (m *Manager) jobTimeout(request api.SolveRequest) time.Duration {
+	timeout := time.Duration(request.TimeoutMs) * time.Millisecond
+	if timeout == 0 {
+		timeout = m.timeout
+	}
+	if m.maxTimeout > 0 && (timeout == 0 || timeout > m.maxTimeout) {
+		timeout = m.maxTimeout
+	}
+
+	return timeout
+}
+
+// Get returns the job with the given id.
+func (m *Manager) Get(ctx context.Context, id string) (api.Job, error) {
+	return m.store.Load(ctx, id)
+}
+
+// Cancel cancels the job with the given id, waiting for it to stop,
+// and returns the job. Cancelling a finished job has no effect.
+func (m *Manager) Cancel(ctx context.Context, id string) (api.Job, error) {
+	m.mu.Lock()
+	exec, ok := m.running[id]
+	if ok {
+		exec.cancelled = true
+		exec.cancel()
+	}
+	m.mu.Unlock()
+
+	if ok {
+		select {
+		case <-exec.done:
+		case <-ctx.Done():
+			return api.Job{}, ctx.Err()
+		}
+	}
+
+	return m.store.Load(ctx, id)
+}
+
+// Close cancels the running jobs and waits for them to stop, keeping
+// their partial results, and returns the number of jobs it cancelled.
+// No jobs can be created once the manager is closed.
+func (m *Manager) Close() int {
+	m.mu.Lock()
+	m.closed = true
+	cancelled := len(m.running)
+	for _, exec := range m.running {
+		exec.cancelled = true
+		exec.cancel()
+	}
+	m.mu.Unlock()
+
+	m.wg.Wait()
+	return cancelled
+}
+
+// execute runs the job, saving its progress and partial solutions
+// periodically.
+func (m *Manager) execute(ctx context.Context, exec *execution, job api.Job, request api.SolveRequest) {
+	defer m.wg.Done()
+	defer close(exec.done)
+	defer exec.cancel()
+
+	job.Status = api.JobStatusRunning
+	m.save(job)
+
+	// Guards the job as the progress may be reported concurrently
+	var mu sync.Mutex
+	lastSave := time.Now()
+	saveSoon := func() {
+		if time.Since(lastSave) >= m.saveInterval {
+			m.save(job)
+			lastSave = time.Now()
+		}
+	}
+
+	response := m.run(ctx, &request, func(solution api.Grid) {
+		mu.Lock()
+		defer mu.Unlock()
+		if request.MaxSolutions > 0 && len(job.Result.Solutions) >= request.MaxSolutions {
+			return
+		}
+
+		job.Result.Solutions = append(job.Result.Solutions, solution)
+		job.Progress.Solutions = len(job.Result.Solutions)
+		saveSoon()
+	}, func(nodes int) {
+		mu.Lock()
+		defer mu.Unlock()
+		if nodes > job.Progress.Nodes {
+			job.Progress.Nodes = nodes
+			saveSoon()
+		}
+	})
+	response.Solutions = job.Result.Solutions
+	job.Result = response
+
+	m.mu.Lock()
+	delete(m.running, job.ID)
+	cancelled := exec.cancelled
+	m.mu.Unlock()
+
+	switch {
+	case cancelled:
+		job.Status = api.JobStatusCancelled
+	case response.Error != nil:
+		job.Status = api.JobStatusFailed
+	default:
+		job.Status = api.JobStatusCompleted
+	}
+
+	finishedAt := time.Now().UTC()
+	job.FinishedAt = &finishedAt
+	m.save(job)
+
+	m.mu.Lock()
+	m.finished = append(m.finished, finishedJob{id: job.ID, finishedAt: finishedAt})
+	m.mu.Unlock()
+}
+
+// save saves the job, logging any failure as there is no caller to
+// return the error to.
+func (m *Manager) save(job api.Job) {
+	job.UpdatedAt = time.Now().UTC()
+	err := m.store.Save(context.Background(), job)
+	if err != nil {
+		log.Err(err).Str("job", job.ID).Msg("failed to save job")
+	}
+}
+
+// prune deletes the finished jobs whose retention has passed.
+func (m *Manager) prune(ctx context.Context) {
+	cutoff := time.Now().Add(-m.retention)
+
+	m.mu.Lock()
+	var expired []finishedJob
+	for len(m.finished) > 0 && m.finished[0].finishedAt.Before(cutoff) {
+		expired = append(expired, m.finished[0])
+		m.finished = m.finished[1:]
+	}
+	m.mu.Unlock()
+
+	for _, job := range expired {
+		err := m.store.Delete(ctx, job.id)
+		if err != nil {
+			log.Err(err).Str("job", job.id).Msg("failed to delete job")
+		}
+	}
+}
+
+// newID returns a random job id.
+func newID() string {
+	bs := make([]byte, 16)
+	_, _ = rand.Read(bs)
+	return hex.EncodeToString(bs)
+}
+
+// detached is a context with the values of its parent which is never
+// cancelled.
+type detached struct {
+	context.Context
+}
+
+func (detached) Deadline() (time.Time, bool) {
+	return time.Time{}, false
+}
+
+func (detached) Done() <-chan struct{} {
+	return nil
+}
+
+func (detached) Err() error {
+	return nil
+}
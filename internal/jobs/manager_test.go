@@ -0,0 +1,142 @@
+package jobs
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/PeterEFinch/sudoku-solver/api"
+)
+
+// blockingRunner yields a solution and then blocks until the context is
+// done.
+func blockingRunner(ctx context.Context, _ *api.SolveRequest, yield func(solution api.Grid), report func(nodes int)) api.SolveResponse {
+	yield(api.Grid{{1}})
+	report(10)
+	<-ctx.Done()
+	return api.SolveResponse{Error: &api.Error{Code: api.ErrorCodeCancelled}}
+}
+
+func TestManager_Cancel(t *testing.T) {
+	manager := NewManager(NewMemoryStore(), blockingRunner, WithSaveInterval(0))
+	defer manager.Close()
+
+	job, err := manager.Create(context.Background(), api.SolveRequest{})
+	if err != nil {
+		t.Fatalf("failed to create job: %v", err)
+	}
+
+	// Waits for the partial solution to be saved
+	for i := 0; ; i++ {
+		job, err = manager.Get(context.Background(), job.ID)
+		if err != nil {
+			t.Fatalf("failed to get job: %v", err)
+		}
+		if len(job.Result.Solutions) == 1 && job.Progress.Nodes == 10 {
+			break
+		}
+		if i == 100 {
+			t.Fatalf("expected a partial solution and progress, got job %+v", job)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if job.Status != api.JobStatusRunning {
+		t.Fatalf("expected status %q, got %q", api.JobStatusRunning, job.Status)
+	}
+
+	job, err = manager.Cancel(context.Background(), job.ID)
+	if err != nil {
+		t.Fatalf("failed to cancel job: %v", err)
+	}
+
+	if job.Status != api.JobStatusCancelled || !job.Finished() || len(job.Result.Solutions) != 1 || job.Progress.Solutions != 1 {
+		t.Fatalf("expected a cancelled job with a partial solution, got %+v", job)
+	}
+}
+
+func TestManager_Get_NotFound(t *testing.T) {
+	manager := NewManager(NewMemoryStore(), blockingRunner)
+	defer manager.Close()
+
+	_, err := manager.Get(context.Background(), "unknown")
+	if err != ErrNotFound {
+		t.Fatalf("expected error %v, got %v", ErrNotFound, err)
+	}
+}
+
+func TestManager_Close(t *testing.T) {
+	manager := NewManager(NewMemoryStore(), blockingRunner, WithSaveInterval(0))
+
+	job, err := manager.Create(context.Background(), api.SolveRequest{})
+	if err != nil {
+		t.Fatalf("failed to create job: %v", err)
+	}
+
+	if cancelled := manager.Close(); cancelled != 1 {
+		t.Fatalf("expected 1 job to be cancelled, got %d", cancelled)
+	}
+
+	job, err = manager.Get(context.Background(), job.ID)
+	if err != nil || job.Status != api.JobStatusCancelled {
+		t.Fatalf("expected a cancelled job, got %+v: %v", job, err)
+	}
+
+	if _, err = manager.Create(context.Background(), api.SolveRequest{}); err != ErrClosed {
+		t.Fatalf("expected error %v, got %v", ErrClosed, err)
+	}
+}
+
+func TestManager_Limits(t *testing.T) {
+	tests := []struct {
+		name         string
+		request      api.SolveRequest
+		timeout      time.Duration
+		maxSolutions int
+	}{
+		{"defaults", api.SolveRequest{}, 20 * time.Millisecond, 3},
+		{"within limits", api.SolveRequest{TimeoutMs: 10, MaxSolutions: 2}, 10 * time.Millisecond, 2},
+		{"capped", api.SolveRequest{TimeoutMs: 60000, MaxSolutions: 10}, 50 * time.Millisecond, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var deadline time.Duration
+			var maxSolutions int
+			run := func(ctx context.Context, request *api.SolveRequest, yield func(solution api.Grid), _ func(nodes int)) api.SolveResponse {
+				end, _ := ctx.Deadline()
+				deadline, maxSolutions = time.Until(end), request.MaxSolutions
+
+				// Ignores the limit, which the manager enforces anyway
+				for i := 0; i < 10; i++ {
+					yield(api.Grid{{i}})
+				}
+				<-ctx.Done()
+				return api.SolveResponse{Error: &api.Error{Code: api.ErrorCodeTimeout}}
+			}
+
+			manager := NewManager(NewMemoryStore(), run, WithTimeouts(20*time.Millisecond, 50*time.Millisecond), WithMaxSolutions(3))
+			job, err := manager.Create(context.Background(), tt.request)
+			if err != nil {
+				t.Fatalf("failed to create job: %v", err)
+			}
+
+			// Waits for the job to time out
+			for i := 0; !job.Finished(); i++ {
+				if i == 100 {
+					t.Fatalf("expected the job to time out, got %+v", job)
+				}
+				time.Sleep(10 * time.Millisecond)
+				job, _ = manager.Get(context.Background(), job.ID)
+			}
+
+			if deadline <= 0 || deadline > tt.timeout {
+				t.Fatalf("expected a timeout of at most %v, got %v", tt.timeout, deadline)
+			}
+
+			if maxSolutions != tt.maxSolutions || len(job.Result.Solutions) != tt.maxSolutions {
+				t.Fatalf("expected %d solutions, got a limit of %d and %d solutions", tt.maxSolutions, maxSolutions, len(job.Result.Solutions))
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package jobs
+
+import (
+	"context"
+	"sync"
+
+	"github.com/PeterEFinch/sudoku-solver/api"
+)
+
+// Store persists jobs. Implementations must be safe for concurrent use.
+type Store interface {
+	// Save creates or replaces the job.
+	Save(ctx context.Context, job api.Job) error
+
+	// Load returns the job with the given id or ErrNotFound.
+	Load(ctx context.Context, id string) (api.Job, error)
+
+	// Delete removes the job with the given id, if it exists.
+	Delete(ctx context.Context, id string) error
+}
+
+// MemoryStore stores jobs in memory.
+type MemoryStore struct {
+	mu   sync.RWMutex
+	jobs map[string]api.Job
+}
+
+// NewMemoryStore returns an empty in-memory store.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		jobs: make(map[string]api.Job),
+	}
+}
+
+func (s *MemoryStore) Save(_ context.Context, job api.Job) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.jobs[job.ID] = job
+	return nil
+}
+
+func (s *MemoryStore) Load(_ context.Context, id string) (api.Job, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	job, ok := s.jobs[id]
+	if !ok {
+		return api.Job{}, ErrNotFound
+	}
+
+	return job, nil
+}
+
+func (s *MemoryStore) Delete(_ context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.jobs, id)
+	return nil
+}
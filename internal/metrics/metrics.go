@@ -0,0 +1,150 @@
+// Package metrics records the Prometheus metrics of the server.
+package metrics
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"github.com/PeterEFinch/sudoku-solver/internal/auth"
+	"github.com/PeterEFinch/sudoku-solver/internal/middleware"
+	"github.com/PeterEFinch/sudoku-solver/internal/sudoku"
+)
+
+const namespace = "sudoku"
+
+// The outcomes of a solve.
+const (
+	OutcomeSolved        = "solved"
+	OutcomeUnsolvable    = "unsolvable"
+	OutcomeTimeout       = "timeout"
+	OutcomeCancelled     = "cancelled"
+	OutcomeQuotaExceeded = "quota_exceeded"
+	OutcomeInvalid       = "invalid"
+)
+
+var (
+	requests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "The number of HTTP requests by handler, method and status code.",
+	}, []string{"handler", "method", "code"})
+
+	requestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "The latency of HTTP requests by handler and method.",
+		Buckets:   prometheus.ExponentialBuckets(0.001, 4, 10),
+	}, []string{"handler", "method"})
+
+	solves = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "solves_total",
+		Help:      "The number of solves by outcome.",
+	}, []string{"outcome"})
+
+	solutions = promauto.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "solve_solutions",
+		Help:      "The number of solutions found by each solve.",
+		Buckets:   []float64{0, 1, 2, 5, 10, 100, 1000},
+	})
+
+	nodes = promauto.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "solve_nodes",
+		Help:      "The number of nodes of the search tree explored by each solve.",
+		Buckets:   prometheus.ExponentialBuckets(1, 10, 8),
+	})
+
+	solvesInFlight = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "solves_in_flight",
+		Help:      "The number of solves currently running.",
+	})
+
+	cacheHits = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "solve_cache_hits_total",
+		Help:      "The number of solves answered from the cache.",
+	})
+
+	cacheMisses = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "solve_cache_misses_total",
+		Help:      "The number of solves which were not cached or bypassed the cache.",
+	})
+)
+
+// Handler returns a handler exposing the metrics in the Prometheus text
+// format.
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
+
+// Instrument returns a handler recording the count and latency of the
+// requests served by the mux. Requests are labelled by the pattern of
+// the mux which matched them, so unknown paths share the "/" pattern.
+func Instrument(mux *http.ServeMux) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		_, pattern := mux.Handler(req)
+		recorder := middleware.NewRecorder(rw)
+
+		start := time.Now()
+		mux.ServeHTTP(recorder, req)
+
+		requestDuration.WithLabelValues(pattern, req.Method).Observe(time.Since(start).Seconds())
+		requests.WithLabelValues(pattern, req.Method, strconv.Itoa(recorder.Status)).Inc()
+	})
+}
+
+// SolveStarted records a solve as in flight. The returned function must
+// be called once the solve finishes to record its result.
+func SolveStarted() func(count int, stats sudoku.Stats, err error) {
+	solvesInFlight.Inc()
+	return func(count int, stats sudoku.Stats, err error) {
+		solvesInFlight.Dec()
+		solves.WithLabelValues(Outcome(err)).Inc()
+		solutions.Observe(float64(count))
+		nodes.Observe(float64(stats.Nodes))
+	}
+}
+
+// CacheLookup records whether a solve was answered from the cache.
+func CacheLookup(hit bool) {
+	if hit {
+		cacheHits.Inc()
+	} else {
+		cacheMisses.Inc()
+	}
+}
+
+// SolveRejected records a solve request which failed validation.
+func SolveRejected() {
+	solves.WithLabelValues(OutcomeInvalid).Inc()
+}
+
+// Outcome returns the outcome of a solve which finished with the error.
+func Outcome(err error) string {
+	switch {
+	case err == nil:
+		return OutcomeSolved
+	case errors.Is(err, sudoku.ErrNoSolution), errors.Is(err, sudoku.ErrGuessRequired):
+		return OutcomeUnsolvable
+	case errors.Is(err, sudoku.ErrTimeout):
+		return OutcomeTimeout
+	case errors.Is(err, sudoku.ErrCancelled):
+		return OutcomeCancelled
+	case errors.Is(err, auth.ErrQuotaExceeded):
+		return OutcomeQuotaExceeded
+	default:
+		// The remaining errors are caused by the grid or the options
+		// of the request
+		return OutcomeInvalid
+	}
+}
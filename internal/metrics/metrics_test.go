@@ -0,0 +1,71 @@
+package metrics
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/PeterEFinch/sudoku-solver/internal/auth"
+	"github.com/PeterEFinch/sudoku-solver/internal/sudoku"
+)
+
+func TestInstrument(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(rw http.ResponseWriter, _ *http.Request) {
+		rw.WriteHeader(http.StatusNotFound)
+	})
+	mux.HandleFunc("/flush", func(rw http.ResponseWriter, _ *http.Request) {
+		if _, ok := rw.(http.Flusher); !ok {
+			t.Error("expected the response to be flushable")
+		}
+	})
+	handler := Instrument(mux)
+
+	for _, path := range []string{"/flush", "/unknown", "/other"} {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	if count := testutil.ToFloat64(requests.WithLabelValues("/flush", http.MethodGet, "200")); count != 1 {
+		t.Fatalf("expected 1 request to /flush, got %v", count)
+	}
+
+	if count := testutil.ToFloat64(requests.WithLabelValues("/", http.MethodGet, "404")); count != 2 {
+		t.Fatalf("expected 2 requests to unknown paths, got %v", count)
+	}
+}
+
+func TestSolveStarted(t *testing.T) {
+	tests := []struct {
+		err     error
+		outcome string
+	}{
+		{nil, OutcomeSolved},
+		{sudoku.ErrNoSolution, OutcomeUnsolvable},
+		{sudoku.ErrGuessRequired, OutcomeUnsolvable},
+		{sudoku.ErrTimeout, OutcomeTimeout},
+		{sudoku.ErrCancelled, OutcomeCancelled},
+		{fmt.Errorf("%w: daily quota of 1000ms used", auth.ErrQuotaExceeded), OutcomeQuotaExceeded},
+		{sudoku.ErrInvalidGrid, OutcomeInvalid},
+	}
+
+	for _, tt := range tests {
+		before := testutil.ToFloat64(solves.WithLabelValues(tt.outcome))
+
+		done := SolveStarted()
+		if inFlight := testutil.ToFloat64(solvesInFlight); inFlight != 1 {
+			t.Fatalf("expected 1 solve in flight, got %v", inFlight)
+		}
+		done(1, sudoku.Stats{Nodes: 10}, tt.err)
+
+		if inFlight := testutil.ToFloat64(solvesInFlight); inFlight != 0 {
+			t.Fatalf("expected no solves in flight, got %v", inFlight)
+		}
+
+		if after := testutil.ToFloat64(solves.WithLabelValues(tt.outcome)); after != before+1 {
+			t.Fatalf("expected error %v to be recorded as %q", tt.err, tt.outcome)
+		}
+	}
+}
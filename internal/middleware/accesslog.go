@@ -0,0 +1,28 @@
+package middleware
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// AccessLog logs every request once it has been handled, along with the
+// status and size of the response and the latency.
+func AccessLog(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		recorder := NewRecorder(rw)
+		start := time.Now()
+		next.ServeHTTP(recorder, req)
+
+		log.Info().
+			Str("request_id", req.Header.Get(HeaderRequestID)).
+			Str("method", req.Method).
+			Str("path", req.URL.Path).
+			Str("remote_addr", req.RemoteAddr).
+			Int("status", recorder.Status).
+			Int("bytes", recorder.Bytes).
+			Float64("latency_ms", float64(time.Since(start))/float64(time.Millisecond)).
+			Msg("request handled")
+	})
+}
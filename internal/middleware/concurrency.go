@@ -0,0 +1,185 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/PeterEFinch/sudoku-solver/api"
+)
+
+// LimitConcurrency limits the number of requests handled concurrently
+// to maxConcurrent. Up to maxQueued further requests wait for their
+// turn, and requests beyond the queue are rejected with 503.
+func LimitConcurrency(maxConcurrent, maxQueued int) Middleware {
+	return NewConcurrencyLimiter(maxConcurrent, maxQueued).Middleware
+}
+
+// ConcurrencyLimiter limits the number of requests handled concurrently,
+// sharing the limit between the HTTP and gRPC servers. It is safe for
+// concurrent use.
+type ConcurrencyLimiter struct {
+	maxConcurrent int
+	maxQueued     int
+	slots         chan struct{}
+
+	// queue holds a token for every request in flight or waiting
+	queue chan struct{}
+}
+
+// NewConcurrencyLimiter returns a limiter allowing maxConcurrent
+// requests at once with up to maxQueued further requests waiting for
+// their turn.
+func NewConcurrencyLimiter(maxConcurrent, maxQueued int) *ConcurrencyLimiter {
+	return &ConcurrencyLimiter{
+		maxConcurrent: maxConcurrent,
+		maxQueued:     maxQueued,
+		slots:         make(chan struct{}, maxConcurrent),
+		queue:         make(chan struct{}, maxConcurrent+maxQueued),
+	}
+}
+
+// Acquire waits for a slot, failing with ErrOverloaded if the queue is
+// full or the error of the context if it is done while queued. The returned function
+// releases the slot and must be called once the request is handled.
+func (l *ConcurrencyLimiter) Acquire(ctx context.Context) (func(), error) {
+	select {
+	case l.queue <- struct{}{}:
+	default:
+		return nil, fmt.Errorf("%w: server is handling the maximum of %d requests", ErrOverloaded, l.maxConcurrent+l.maxQueued)
+	}
+
+	select {
+	case l.slots <- struct{}{}:
+		return func() {
+			<-l.slots
+			<-l.queue
+		}, nil
+	case <-ctx.Done():
+		// The client is gone or the deadline passed while queued
+		<-l.queue
+		return nil, ctx.Err()
+	}
+}
+
+// Middleware limits the requests of the handler, rejecting those which
+// do not get a slot with 503.
+func (l *ConcurrencyLimiter) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		release, err := l.Acquire(req.Context())
+		if err != nil {
+			// Only a full queue is worth retrying
+			if errors.Is(err, ErrOverloaded) {
+				rw.Header().Set("Retry-After", "1")
+			}
+			WriteError(rw, req, http.StatusServiceUnavailable, api.ErrorCodeOverloaded, err.Error())
+			return
+		}
+		defer release()
+
+		next.ServeHTTP(rw, req)
+	})
+}
+
+// limiterKey is the context key of the concurrency limiter of solves.
+type limiterKey struct{}
+
+// Share makes the limiter available to the handler through the context
+// of each request, limiting each solve it runs rather than the request
+// itself, see AcquireSolve.
+func (l *ConcurrencyLimiter) Share(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		next.ServeHTTP(rw, req.WithContext(WithConcurrencyLimiter(req.Context(), l)))
+	})
+}
+
+// WithConcurrencyLimiter returns a copy of the context with the limiter
+// of its solves, see AcquireSolve.
+func WithConcurrencyLimiter(ctx context.Context, l *ConcurrencyLimiter) context.Context {
+	return context.WithValue(ctx, limiterKey{}, l)
+}
+
+// AcquireSolve waits for a slot of the limiter of the context for a
+// single solve, see Acquire. The solve runs at once if the context has
+// no limiter.
+func AcquireSolve(ctx context.Context) (func(), error) {
+	l, ok := ctx.Value(limiterKey{}).(*ConcurrencyLimiter)
+	if !ok {
+		return func() {}, nil
+	}
+
+	return l.Acquire(ctx)
+}
+
+// UnaryServerInterceptor limits the unary calls of a gRPC server,
+// rejecting those which do not get a slot with ResourceExhausted.
+func (l *ConcurrencyLimiter) UnaryServerInterceptor(ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	release, err := l.Acquire(ctx)
+	if err != nil {
+		return nil, acquireStatus(err)
+	}
+	defer release()
+
+	return handler(ctx, req)
+}
+
+// StreamServerInterceptor limits the streaming calls of a gRPC server,
+// rejecting those which do not get a slot with ResourceExhausted.
+func (l *ConcurrencyLimiter) StreamServerInterceptor(srv interface{}, stream grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	release, err := l.Acquire(stream.Context())
+	if err != nil {
+		return acquireStatus(err)
+	}
+	defer release()
+
+	return handler(srv, stream)
+}
+
+// acquireStatus converts an error of Acquire into a gRPC status error.
+func acquireStatus(err error) error {
+	if errors.Is(err, ErrOverloaded) {
+		return status.Error(codes.ResourceExhausted, err.Error())
+	}
+
+	return status.FromContextError(err).Err()
+}
+
+// MaxTimeout sets a deadline on the context of each request, which caps
+// the timeout_ms of solves as their context is derived from it.
+func MaxTimeout(timeout time.Duration) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			ctx, cancel := context.WithTimeout(req.Context(), timeout)
+			defer cancel()
+
+			next.ServeHTTP(rw, req.WithContext(ctx))
+		})
+	}
+}
+
+// defaultTimeoutKey is the context key of the default timeout.
+type defaultTimeoutKey struct{}
+
+// DefaultTimeout sets the timeout of solves which do not give a
+// timeout_ms, see DefaultTimeoutFrom.
+func DefaultTimeout(timeout time.Duration) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			ctx := context.WithValue(req.Context(), defaultTimeoutKey{}, timeout)
+			next.ServeHTTP(rw, req.WithContext(ctx))
+		})
+	}
+}
+
+// DefaultTimeoutFrom returns the default timeout set by DefaultTimeout,
+// if any.
+func DefaultTimeoutFrom(ctx context.Context) (time.Duration, bool) {
+	timeout, ok := ctx.Value(defaultTimeoutKey{}).(time.Duration)
+	return timeout, ok
+}
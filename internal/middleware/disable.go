@@ -0,0 +1,26 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/PeterEFinch/sudoku-solver/api"
+)
+
+// Disable rejects requests for the paths, and the paths beneath them,
+// with 404 as if they had no handler.
+func Disable(paths ...string) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			for _, path := range paths {
+				if req.URL.Path == path || strings.HasPrefix(req.URL.Path, strings.TrimSuffix(path, "/")+"/") {
+					WriteError(rw, req, http.StatusNotFound, api.ErrorCodeNotFound, fmt.Sprintf("no handler for path %s", req.URL.Path))
+					return
+				}
+			}
+
+			next.ServeHTTP(rw, req)
+		})
+	}
+}
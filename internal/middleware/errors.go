@@ -0,0 +1,14 @@
+package middleware
+
+const (
+	ErrBodyTooLarge Error = "body_too_large"
+	ErrNoHijacker   Error = "hijack_not_supported"
+	ErrOverloaded   Error = "overloaded"
+	ErrRateLimited  Error = "rate_limited"
+)
+
+type Error string
+
+func (e Error) Error() string {
+	return string(e)
+}
@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"io"
+	"net/http"
+)
+
+// LimitBody limits the body of each request to maxBytes. Reading beyond
+// the limit fails with ErrBodyTooLarge.
+func LimitBody(maxBytes int64) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			if req.ContentLength > maxBytes {
+				// Fails fast without reading a body which is known to be too large
+				req.Body = &limitedBody{ReadCloser: req.Body, remaining: -1}
+			} else {
+				req.Body = &limitedBody{ReadCloser: req.Body, remaining: maxBytes}
+			}
+
+			next.ServeHTTP(rw, req)
+		})
+	}
+}
+
+// limitedBody fails with ErrBodyTooLarge once more than remaining
+// bytes are read. The remaining bytes are negative once it has failed.
+type limitedBody struct {
+	io.ReadCloser
+	remaining int64
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	if b.remaining < 0 {
+		return 0, ErrBodyTooLarge
+	}
+
+	// Reads one byte more than remaining to detect bodies which are too large
+	if int64(len(p)) > b.remaining+1 {
+		p = p[:b.remaining+1]
+	}
+
+	n, err := b.ReadCloser.Read(p)
+	if int64(n) <= b.remaining {
+		b.remaining -= int64(n)
+		return n, err
+	}
+
+	n, b.remaining = int(b.remaining), -1
+	return n, ErrBodyTooLarge
+}
@@ -0,0 +1,95 @@
+// Package middleware contains the middleware wrapping every handler of
+// the server.
+package middleware
+
+import (
+	"bufio"
+	"encoding/json"
+	"net"
+	"net/http"
+
+	"github.com/PeterEFinch/sudoku-solver/api"
+)
+
+// Middleware wraps a handler with additional behaviour.
+type Middleware func(next http.Handler) http.Handler
+
+// Chain wraps the handler with the middlewares, where the first
+// middleware is the outermost and so sees each request first.
+func Chain(handler http.Handler, middlewares ...Middleware) http.Handler {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		handler = middlewares[i](handler)
+	}
+
+	return handler
+}
+
+// WriteError writes an error response in the format of the handlers.
+func WriteError(rw http.ResponseWriter, req *http.Request, status int, code, message string) {
+	bs, _ := json.Marshal(&api.ErrorResponse{
+		Error: &api.Error{
+			Code:      code,
+			Message:   message,
+			RequestID: req.Header.Get(HeaderRequestID),
+		},
+	})
+
+	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(status)
+	_, _ = rw.Write(bs)
+}
+
+// Recorder records the status and size of the response written by a
+// handler. It forwards flushes and hijacks so streamed responses and
+// websockets still work.
+type Recorder struct {
+	http.ResponseWriter
+
+	// Status is the status of the response, which is 200 until the
+	// handler writes a header.
+	Status int
+
+	// Bytes is the number of bytes of the body written.
+	Bytes int
+
+	// WroteHeader is true once the header has been written.
+	WroteHeader bool
+}
+
+// NewRecorder returns a recorder of the response.
+func NewRecorder(rw http.ResponseWriter) *Recorder {
+	return &Recorder{ResponseWriter: rw, Status: http.StatusOK}
+}
+
+func (r *Recorder) WriteHeader(status int) {
+	if !r.WroteHeader {
+		r.Status = status
+		r.WroteHeader = true
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *Recorder) Write(bs []byte) (int, error) {
+	r.WroteHeader = true
+	n, err := r.ResponseWriter.Write(bs)
+	r.Bytes += n
+	return n, err
+}
+
+func (r *Recorder) Flush() {
+	if flusher, ok := r.ResponseWriter.(http.Flusher); ok {
+		r.WroteHeader = true
+		flusher.Flush()
+	}
+}
+
+func (r *Recorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := r.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, ErrNoHijacker
+	}
+
+	r.Status = http.StatusSwitchingProtocols
+	r.WroteHeader = true
+	return hijacker.Hijack()
+}
@@ -0,0 +1,243 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/PeterEFinch/sudoku-solver/api"
+)
+
+func TestRequestID(t *testing.T) {
+	tests := []struct {
+		name      string
+		id        string
+		generated bool
+	}{
+		{"propagated", "client-id", false},
+		{"missing", "", true},
+		{"too long", strings.Repeat("a", maxRequestIDLength+1), true},
+		{"unprintable", "id\n", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var seen string
+			handler := RequestID(http.HandlerFunc(func(_ http.ResponseWriter, req *http.Request) {
+				seen = req.Header.Get(HeaderRequestID)
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set(HeaderRequestID, tt.id)
+			rw := httptest.NewRecorder()
+			handler.ServeHTTP(rw, req)
+
+			if seen == "" || seen != rw.Header().Get(HeaderRequestID) {
+				t.Fatalf("expected the handler and response to have the same id, got %q and %q", seen, rw.Header().Get(HeaderRequestID))
+			}
+
+			if (seen != tt.id) != tt.generated {
+				t.Fatalf("expected generated %v for id %q, got %q", tt.generated, tt.id, seen)
+			}
+		})
+	}
+}
+
+func TestRecover(t *testing.T) {
+	handler := Chain(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		panic("failed")
+	}), RequestID, Recover)
+
+	rw := httptest.NewRecorder()
+	handler.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if rw.Code != http.StatusInternalServerError {
+		t.Fatalf("expected status %d, got %d", http.StatusInternalServerError, rw.Code)
+	}
+
+	response := &api.ErrorResponse{}
+	err := json.Unmarshal(rw.Body.Bytes(), response)
+	if err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+
+	if response.Error == nil || response.Error.Code != api.ErrorCodeInternal || response.Error.RequestID != rw.Header().Get(HeaderRequestID) {
+		t.Fatalf("expected an internal error with the request id, got %+v", response.Error)
+	}
+}
+
+func TestLimitBody(t *testing.T) {
+	tests := []struct {
+		name          string
+		body          string
+		contentLength int64
+		err           error
+	}{
+		{"within limit", "12345", 5, nil},
+		{"too large", "123456", 6, ErrBodyTooLarge},
+		{"unknown length", "123456", -1, ErrBodyTooLarge},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var body []byte
+			var err error
+			handler := LimitBody(5)(http.HandlerFunc(func(_ http.ResponseWriter, req *http.Request) {
+				body, err = io.ReadAll(req.Body)
+			}))
+
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tt.body))
+			req.ContentLength = tt.contentLength
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("expected error %v, got %v", tt.err, err)
+			}
+
+			if tt.err == nil && string(body) != tt.body {
+				t.Fatalf("expected body %q, got %q", tt.body, body)
+			}
+		})
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	handler := RateLimit(0.001, 2)(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+
+	tests := []struct {
+		name       string
+		remoteAddr string
+		clientID   string
+		status     int
+	}{
+		{"first", "10.0.0.1:1234", "", http.StatusOK},
+		{"second from another port", "10.0.0.1:5678", "", http.StatusOK},
+		{"burst exceeded", "10.0.0.1:1234", "", http.StatusTooManyRequests},
+		{"another client", "10.0.0.2:1234", "", http.StatusOK},
+		{"client id", "10.0.0.1:1234", "key", http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.RemoteAddr = tt.remoteAddr
+		if tt.clientID != "" {
+			req = req.WithContext(WithClientID(req.Context(), tt.clientID))
+		}
+		rw := httptest.NewRecorder()
+		handler.ServeHTTP(rw, req)
+
+		if rw.Code != tt.status {
+			t.Fatalf("%s: expected status %d, got %d", tt.name, tt.status, rw.Code)
+		}
+
+		if tt.status == http.StatusTooManyRequests && rw.Header().Get("Retry-After") == "" {
+			t.Fatalf("%s: expected a Retry-After header", tt.name)
+		}
+	}
+}
+
+func TestLimitConcurrency(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	handler := LimitConcurrency(1, 1)(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		started <- struct{}{}
+		<-release
+	}))
+
+	// Occupies the only slot and then the only place in the queue
+	codes := make(chan int, 2)
+	for i := 0; i < 2; i++ {
+		go func() {
+			rw := httptest.NewRecorder()
+			handler.ServeHTTP(rw, httptest.NewRequest(http.MethodPost, "/solve", nil))
+			codes <- rw.Code
+		}()
+	}
+	<-started
+
+	// Probes with cancelled requests, which fail without waiting for a
+	// slot, until the second request is queued
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	var rw *httptest.ResponseRecorder
+	for i := 0; i < 100; i++ {
+		rw = httptest.NewRecorder()
+		handler.ServeHTTP(rw, httptest.NewRequest(http.MethodPost, "/solve", nil).WithContext(ctx))
+		if rw.Header().Get("Retry-After") != "" {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	if rw.Code != http.StatusServiceUnavailable || rw.Header().Get("Retry-After") == "" {
+		t.Fatalf("expected status %d once the queue is full, got %d", http.StatusServiceUnavailable, rw.Code)
+	}
+
+	close(release)
+	<-started
+	for i := 0; i < 2; i++ {
+		if code := <-codes; code != http.StatusOK {
+			t.Fatalf("expected the queued requests to be handled, got status %d", code)
+		}
+	}
+}
+
+func TestMaxTimeout(t *testing.T) {
+	handler := MaxTimeout(time.Second)(http.HandlerFunc(func(_ http.ResponseWriter, req *http.Request) {
+		deadline, ok := req.Context().Deadline()
+		if !ok || time.Until(deadline) > time.Second {
+			t.Errorf("expected a deadline within a second, got %v", deadline)
+		}
+	}))
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/solve", nil))
+}
+
+func TestDefaultTimeout(t *testing.T) {
+	handler := DefaultTimeout(time.Second)(http.HandlerFunc(func(_ http.ResponseWriter, req *http.Request) {
+		timeout, ok := DefaultTimeoutFrom(req.Context())
+		if !ok || timeout != time.Second {
+			t.Errorf("expected a default timeout of 1s, got %v", timeout)
+		}
+	}))
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/solve", nil))
+
+	if _, ok := DefaultTimeoutFrom(context.Background()); ok {
+		t.Fatalf("expected no default timeout without the middleware")
+	}
+}
+
+func TestDisable(t *testing.T) {
+	handler := Disable("/jobs", "/play")(http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+		rw.WriteHeader(http.StatusOK)
+	}))
+
+	tests := []struct {
+		path   string
+		status int
+	}{
+		{"/jobs", http.StatusNotFound},
+		{"/jobs/id", http.StatusNotFound},
+		{"/play", http.StatusNotFound},
+		{"/playground", http.StatusOK},
+		{"/solve", http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			rw := httptest.NewRecorder()
+			handler.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			if rw.Code != tt.status {
+				t.Fatalf("expected status %d, got %d", tt.status, rw.Code)
+			}
+		})
+	}
+}
@@ -0,0 +1,219 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"github.com/PeterEFinch/sudoku-solver/api"
+)
+
+// clientIDKey is the context key of the id of the client.
+type clientIDKey struct{}
+
+// WithClientID returns a copy of the context identifying the client of
+// the request, e.g. by its authenticated API key.
+func WithClientID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, clientIDKey{}, id)
+}
+
+// idleLimiterTTL is how long the bucket of a client is kept after its
+// last request. A new client starts with a full bucket.
+const idleLimiterTTL = 10 * time.Minute
+
+// RateLimit limits each client to perSecond requests per second on
+// average with bursts of up to burst requests, rejecting the requests
+// beyond the limit with 429. Clients are identified by the id set using
+// WithClientID, or by their IP address if they have none.
+func RateLimit(perSecond float64, burst int) Middleware {
+	return NewRateLimiter(perSecond, burst).Middleware
+}
+
+// RateLimiter limits the rate of the requests of each client, sharing
+// the limit between the HTTP and gRPC servers. It is safe for
+// concurrent use.
+type RateLimiter struct {
+	perSecond float64
+	limiters  *rateLimiters
+}
+
+// NewRateLimiter returns a limiter allowing each client perSecond
+// requests per second on average with bursts of up to burst requests.
+func NewRateLimiter(perSecond float64, burst int) *RateLimiter {
+	return &RateLimiter{
+		perSecond: perSecond,
+		limiters: &rateLimiters{
+			limit:    rate.Limit(perSecond),
+			burst:    burst,
+			limiters: make(map[string]*clientLimiter),
+		},
+	}
+}
+
+// allow takes a token of the client, failing with ErrRateLimited and
+// the delay until the next token if there is none.
+func (l *RateLimiter) allow(key string) (time.Duration, error) {
+	reservation := l.limiters.get(key, time.Now()).Reserve()
+	if delay := reservation.Delay(); delay > 0 {
+		// Returns the token as the request is not served
+		reservation.Cancel()
+		return delay, fmt.Errorf("%w: rate limit of %g requests per second exceeded", ErrRateLimited, l.perSecond)
+	}
+
+	return 0, nil
+}
+
+// Check fails with ErrRateLimited and the delay until the next token if
+// the client with the remote address, ignoring any id of the client, has
+// no token left. Otherwise it returns a function taking a token, e.g.
+// once the request turns out to count against the limit.
+func (l *RateLimiter) Check(remoteAddr string) (func(), time.Duration, error) {
+	limiter := l.limiters.get(clientKey(context.Background(), remoteAddr), time.Now())
+	if tokens := limiter.Tokens(); tokens < 1 {
+		delay := time.Duration((1 - tokens) / float64(limiter.Limit()) * float64(time.Second))
+		return nil, delay, fmt.Errorf("%w: rate limit of %g requests per second exceeded", ErrRateLimited, l.perSecond)
+	}
+
+	return func() { limiter.Allow() }, 0, nil
+}
+
+// Middleware limits the requests of the handler, rejecting those beyond
+// the limit with 429.
+func (l *RateLimiter) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		delay, err := l.allow(clientKey(req.Context(), req.RemoteAddr))
+		if err != nil {
+			WriteRateLimited(rw, req, delay, err)
+			return
+		}
+
+		next.ServeHTTP(rw, req)
+	})
+}
+
+// UnaryServerInterceptor limits the unary calls of a gRPC server,
+// rejecting those beyond the limit with ResourceExhausted.
+func (l *RateLimiter) UnaryServerInterceptor(ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if err := l.allowCall(ctx); err != nil {
+		return nil, err
+	}
+
+	return handler(ctx, req)
+}
+
+// StreamServerInterceptor limits the streaming calls of a gRPC server,
+// rejecting those beyond the limit with ResourceExhausted.
+func (l *RateLimiter) StreamServerInterceptor(srv interface{}, stream grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if err := l.allowCall(stream.Context()); err != nil {
+		return err
+	}
+
+	return handler(srv, stream)
+}
+
+// allowCall takes a token of the client of a gRPC call, returning a
+// status error telling the client when to retry if there is none.
+func (l *RateLimiter) allowCall(ctx context.Context) error {
+	delay, err := l.allow(clientKey(ctx, PeerAddress(ctx)))
+	if err != nil {
+		return RateLimitedError(ctx, delay, err)
+	}
+
+	return nil
+}
+
+// PeerAddress returns the address of the peer of a gRPC call, or an
+// empty string if it is unknown.
+func PeerAddress(ctx context.Context) string {
+	if p, ok := peer.FromContext(ctx); ok {
+		return p.Addr.String()
+	}
+
+	return ""
+}
+
+// WriteRateLimited writes a 429 response for the error telling the
+// client to retry after the delay.
+func WriteRateLimited(rw http.ResponseWriter, req *http.Request, delay time.Duration, err error) {
+	rw.Header().Set("Retry-After", retryAfter(delay))
+	WriteError(rw, req, http.StatusTooManyRequests, api.ErrorCodeRateLimited, err.Error())
+}
+
+// RateLimitedError returns a ResourceExhausted status error for the
+// error of a gRPC call, telling the client to retry after the delay.
+func RateLimitedError(ctx context.Context, delay time.Duration, err error) error {
+	_ = grpc.SetHeader(ctx, metadata.Pairs("retry-after", retryAfter(delay)))
+	return status.Error(codes.ResourceExhausted, err.Error())
+}
+
+// retryAfter returns the delay in whole seconds, rounded up.
+func retryAfter(delay time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(delay.Seconds())))
+}
+
+// clientKey returns the key identifying the client of the request with
+// the context and remote address.
+func clientKey(ctx context.Context, remoteAddr string) string {
+	if id, ok := ctx.Value(clientIDKey{}).(string); ok {
+		return "id:" + id
+	}
+
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		host = remoteAddr
+	}
+
+	return "ip:" + host
+}
+
+// rateLimiters holds the token bucket of each client.
+type rateLimiters struct {
+	limit rate.Limit
+	burst int
+
+	mu       sync.Mutex
+	limiters map[string]*clientLimiter
+	swept    time.Time
+}
+
+type clientLimiter struct {
+	*rate.Limiter
+	lastSeen time.Time
+}
+
+// get returns the limiter of the client, deleting the limiters of idle
+// clients at most once per idleLimiterTTL.
+func (l *rateLimiters) get(key string, now time.Time) *rate.Limiter {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if now.Sub(l.swept) > idleLimiterTTL {
+		for k, limiter := range l.limiters {
+			if now.Sub(limiter.lastSeen) > idleLimiterTTL {
+				delete(l.limiters, k)
+			}
+		}
+		l.swept = now
+	}
+
+	limiter, ok := l.limiters[key]
+	if !ok {
+		limiter = &clientLimiter{Limiter: rate.NewLimiter(l.limit, l.burst)}
+		l.limiters[key] = limiter
+	}
+	limiter.lastSeen = now
+
+	return limiter.Limiter
+}
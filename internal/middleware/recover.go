@@ -0,0 +1,45 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/PeterEFinch/sudoku-solver/api"
+)
+
+// Recover recovers from panics in the handler, logging the panic and
+// returning an internal error to the client if the handler has not
+// started writing the response.
+func Recover(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		recorder := NewRecorder(rw)
+		defer func() {
+			recovered := recover()
+			if recovered == nil {
+				return
+			}
+
+			// Aborting a handler is intended to close the connection
+			if recovered == http.ErrAbortHandler {
+				panic(recovered)
+			}
+
+			log.Error().
+				Str("request_id", req.Header.Get(HeaderRequestID)).
+				Str("panic", fmt.Sprint(recovered)).
+				Str("stack", string(debug.Stack())).
+				Msg("handler panicked")
+
+			if recorder.WroteHeader {
+				return
+			}
+
+			WriteError(rw, req, http.StatusInternalServerError, api.ErrorCodeInternal, "internal server error")
+		}()
+
+		next.ServeHTTP(recorder, req)
+	})
+}
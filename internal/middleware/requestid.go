@@ -0,0 +1,53 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+)
+
+// HeaderRequestID is the header identifying a request.
+const HeaderRequestID = "X-Request-ID"
+
+// maxRequestIDLength is the maximum length of a request id given by a
+// client. Longer ids are replaced so they cannot flood the logs.
+const maxRequestIDLength = 128
+
+// RequestID sets the X-Request-ID header of each request and its
+// response. The id given by the client is kept if it is valid,
+// otherwise a random id is generated.
+func RequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		id := req.Header.Get(HeaderRequestID)
+		if !validRequestID(id) {
+			id = newRequestID()
+			req.Header.Set(HeaderRequestID, id)
+		}
+
+		rw.Header().Set(HeaderRequestID, id)
+		next.ServeHTTP(rw, req)
+	})
+}
+
+// validRequestID returns true if the id is non-empty, not too long and
+// contains only printable ASCII characters.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+
+	for i := 0; i < len(id); i++ {
+		if id[i] < '!' || id[i] > '~' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// newRequestID returns a random 16 character hexadecimal id.
+func newRequestID() string {
+	bs := make([]byte, 8)
+	_, _ = rand.Read(bs)
+	return hex.EncodeToString(bs)
+}
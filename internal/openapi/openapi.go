@@ -0,0 +1,437 @@
+// Package openapi generates the OpenAPI document of the HTTP API from
+// the types of the api package.
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/PeterEFinch/sudoku-solver/api"
+)
+
+// object is a JSON object of the document. Objects are marshalled with
+// sorted keys, so the document is deterministic.
+type object = map[string]interface{}
+
+// enums are the values of the string types of the api package which
+// only allow a fixed set of values.
+var enums = map[reflect.Type][]string{
+	reflect.TypeOf(api.JobStatus("")): {
+		string(api.JobStatusPending),
+		string(api.JobStatusRunning),
+		string(api.JobStatusCompleted),
+		string(api.JobStatusFailed),
+		string(api.JobStatusCancelled),
+	},
+}
+
+// operation describes a single method of a path.
+type operation struct {
+	path    string
+	method  string
+	summary string
+
+	// request is the body of the request, if any.
+	request interface{}
+
+	// responses maps each status to the body of the response, which
+	// is nil if the response has no body.
+	responses map[int]interface{}
+
+	// streamed is set if the operation can stream SolveEvents.
+	streamed bool
+
+	// public is set if the operation does not require a client API key.
+	public bool
+}
+
+// operations are the operations of the HTTP API.
+var operations = []operation{
+	{
+		path:    "/solve",
+		method:  "post",
+		summary: "Solves a grid. Setting the Accept header to application/x-ndjson or text/event-stream streams each solution as it is found.",
+		request: api.SolveRequest{},
+		responses: map[int]interface{}{
+			200: api.SolveResponse{},
+			400: api.ErrorResponse{},
+			413: api.ErrorResponse{},
+			409: api.SolveResponse{},
+			422: api.SolveResponse{},
+			503: api.SolveResponse{},
+			504: api.SolveResponse{},
+		},
+		streamed: true,
+	},
+	{
+		path:    "/solve/batch",
+		method:  "post",
+		summary: "Solves many grids concurrently. The body may also be newline delimited SolveRequests.",
+		request: api.BatchSolveRequest{},
+		responses: map[int]interface{}{
+			200: api.BatchSolveResponse{},
+			400: api.ErrorResponse{},
+			413: api.ErrorResponse{},
+			503: api.ErrorResponse{},
+		},
+	},
+	{
+		path:    "/grade",
+		method:  "post",
+		summary: "Grades the difficulty of a grid with a unique solution.",
+		request: api.GradeRequest{},
+		responses: map[int]interface{}{
+			200: api.GradeResponse{},
+			400: api.ErrorResponse{},
+			413: api.ErrorResponse{},
+			422: api.ErrorResponse{},
+			503: api.ErrorResponse{},
+			504: api.ErrorResponse{},
+		},
+	},
+	{
+		path:    "/generate",
+		method:  "post",
+		summary: "Generates a puzzle of the requested difficulty.",
+		request: api.GenerateRequest{},
+		responses: map[int]interface{}{
+			200: api.GenerateResponse{},
+			400: api.ErrorResponse{},
+			413: api.ErrorResponse{},
+			503: api.ErrorResponse{},
+			504: api.ErrorResponse{},
+		},
+	},
+	{
+		path:    "/jobs",
+		method:  "post",
+		summary: "Starts an asynchronous job solving the request.",
+		request: api.SolveRequest{},
+		responses: map[int]interface{}{
+			202: api.Job{},
+			400: api.ErrorResponse{},
+			413: api.ErrorResponse{},
+			503: api.ErrorResponse{},
+		},
+	},
+	{
+		path:    "/jobs/{id}",
+		method:  "get",
+		summary: "Returns the status of the job and the solutions found so far.",
+		responses: map[int]interface{}{
+			200: api.Job{},
+			404: api.ErrorResponse{},
+		},
+	},
+	{
+		path:    "/jobs/{id}",
+		method:  "delete",
+		summary: "Cancels the job, keeping the solutions found so far.",
+		responses: map[int]interface{}{
+			200: api.Job{},
+			404: api.ErrorResponse{},
+		},
+	},
+	{
+		path:    "/play",
+		method:  "get",
+		summary: "Upgrades to a WebSocket play session. The player sends PlayMessages and receives a PlayEvent in reply to each.",
+		responses: map[int]interface{}{
+			101: nil,
+			400: nil,
+		},
+	},
+	{
+		path:    "/health",
+		method:  "get",
+		summary: "Deprecated alias of /readyz.",
+		responses: map[int]interface{}{
+			200: api.HealthResponse{},
+			503: api.HealthResponse{},
+		},
+		public: true,
+	},
+	{
+		path:    "/livez",
+		method:  "get",
+		summary: "Returns 200 while the server is serving requests.",
+		responses: map[int]interface{}{
+			200: api.HealthResponse{},
+		},
+		public: true,
+	},
+	{
+		path:    "/readyz",
+		method:  "get",
+		summary: "Returns 200 if the server accepts new work, or 503 once it is draining to shut down.",
+		responses: map[int]interface{}{
+			200: api.HealthResponse{},
+			503: api.HealthResponse{},
+		},
+		public: true,
+	},
+	{
+		path:    "/version",
+		method:  "get",
+		summary: "Returns the build info of the server.",
+		responses: map[int]interface{}{
+			200: api.BuildInfo{},
+		},
+		public: true,
+	},
+	{
+		path:    "/openapi.json",
+		method:  "get",
+		summary: "Returns this document.",
+		responses: map[int]interface{}{
+			200: nil,
+		},
+		public: true,
+	},
+	{
+		path:    "/admin/keys",
+		method:  "post",
+		summary: "Issues an API key. Requires the admin key.",
+		request: api.CreateAPIKeyRequest{},
+		responses: map[int]interface{}{
+			201: api.CreateAPIKeyResponse{},
+			400: api.ErrorResponse{},
+			401: api.ErrorResponse{},
+			403: api.ErrorResponse{},
+			413: api.ErrorResponse{},
+		},
+		public: true,
+	},
+	{
+		path:    "/admin/keys",
+		method:  "get",
+		summary: "Lists the issued API keys and their usage today. Requires the admin key.",
+		responses: map[int]interface{}{
+			200: api.APIKeyList{},
+			401: api.ErrorResponse{},
+			403: api.ErrorResponse{},
+		},
+		public: true,
+	},
+	{
+		path:    "/admin/keys/{id}",
+		method:  "delete",
+		summary: "Revokes the API key. Requires the admin key.",
+		responses: map[int]interface{}{
+			200: api.APIKey{},
+			401: api.ErrorResponse{},
+			403: api.ErrorResponse{},
+			404: api.ErrorResponse{},
+		},
+		public: true,
+	},
+}
+
+// messages are the types which are not the body of any operation but
+// are part of the contract, such as the messages of a play session.
+var messages = []interface{}{
+	api.PlayMessage{},
+	api.PlayEvent{},
+}
+
+// Generate returns the OpenAPI document of the HTTP API.
+func Generate() ([]byte, error) {
+	g := &generator{schemas: object{}}
+
+	paths := object{}
+	for _, op := range operations {
+		item, ok := paths[op.path].(object)
+		if !ok {
+			item = object{}
+			paths[op.path] = item
+		}
+		item[op.method] = g.operation(op)
+
+		if strings.Contains(op.path, "{id}") {
+			item["parameters"] = []object{{
+				"name":     "id",
+				"in":       "path",
+				"required": true,
+				"schema":   object{"type": "string"},
+			}}
+		}
+	}
+
+	for _, message := range messages {
+		g.schema(reflect.TypeOf(message))
+	}
+
+	document := object{
+		"openapi": "3.0.3",
+		"info": object{
+			"title":   "Sudoku Solver",
+			"version": "1.0.0",
+		},
+		"paths": paths,
+		"components": object{
+			"schemas": g.schemas,
+			"securitySchemes": object{
+				"apiKey": object{"type": "apiKey", "in": "header", "name": "X-API-Key"},
+				"bearer": object{"type": "http", "scheme": "bearer"},
+			},
+		},
+		// Authentication is only required if the server has API keys
+		"security": []object{{}, {"apiKey": []string{}}, {"bearer": []string{}}},
+	}
+
+	bs, err := json.MarshalIndent(document, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return append(bs, '\n'), nil
+}
+
+// generator collects the schemas of the named types referenced by the
+// operations.
+type generator struct {
+	schemas object
+}
+
+// operation returns the operation object of the operation.
+func (g *generator) operation(op operation) object {
+	// Every request may be rejected by the rate limit of the server
+	responses := object{
+		"429": object{
+			"description": http.StatusText(http.StatusTooManyRequests),
+			"content":     object{"application/json": object{"schema": g.schema(reflect.TypeOf(api.ErrorResponse{}))}},
+		},
+	}
+	// Every other request may be rejected for its missing or invalid key
+	if !op.public {
+		responses["401"] = object{
+			"description": http.StatusText(http.StatusUnauthorized),
+			"content":     object{"application/json": object{"schema": g.schema(reflect.TypeOf(api.ErrorResponse{}))}},
+		}
+	}
+	for status, body := range op.responses {
+		response := object{"description": http.StatusText(status)}
+		if body != nil {
+			content := object{"application/json": object{"schema": g.schema(reflect.TypeOf(body))}}
+			if op.streamed && status == 200 {
+				event := object{"schema": g.schema(reflect.TypeOf(api.SolveEvent{}))}
+				content["application/x-ndjson"] = event
+				content["text/event-stream"] = event
+			}
+			response["content"] = content
+		}
+		responses[fmt.Sprint(status)] = response
+	}
+
+	result := object{
+		"summary":   op.summary,
+		"responses": responses,
+	}
+	if op.request != nil {
+		result["requestBody"] = object{
+			"required": true,
+			"content": object{
+				"application/json": object{"schema": g.schema(reflect.TypeOf(op.request))},
+			},
+		}
+	}
+
+	return result
+}
+
+// schema returns the schema of the type, adding a component for each
+// named type of the api package and returning a reference to it.
+func (g *generator) schema(t reflect.Type) object {
+	if t.Kind() == reflect.Ptr {
+		return g.schema(t.Elem())
+	}
+
+	if t == reflect.TypeOf(time.Time{}) {
+		return object{"type": "string", "format": "date-time"}
+	}
+
+	if t.PkgPath() == reflect.TypeOf(api.Grid{}).PkgPath() && t.Name() != "" {
+		if _, ok := g.schemas[t.Name()]; !ok {
+			// Reserves the name first to stop recursive types looping
+			g.schemas[t.Name()] = object{}
+			g.schemas[t.Name()] = g.inline(t)
+		}
+
+		return object{"$ref": "#/components/schemas/" + t.Name()}
+	}
+
+	return g.inline(t)
+}
+
+// inline returns the schema of the type without referencing it.
+func (g *generator) inline(t reflect.Type) object {
+	switch t.Kind() {
+	case reflect.Struct:
+		return g.object(t)
+	case reflect.Slice, reflect.Array:
+		return object{"type": "array", "items": g.schema(t.Elem())}
+	case reflect.Map:
+		return object{"type": "object", "additionalProperties": g.schema(t.Elem())}
+	case reflect.String:
+		schema := object{"type": "string"}
+		if values, ok := enums[t]; ok {
+			schema["enum"] = values
+		}
+		return schema
+	case reflect.Bool:
+		return object{"type": "boolean"}
+	case reflect.Int, reflect.Int32:
+		return object{"type": "integer", "format": "int32"}
+	case reflect.Int64:
+		return object{"type": "integer", "format": "int64"}
+	case reflect.Float32, reflect.Float64:
+		return object{"type": "number", "format": "double"}
+	default:
+		panic(fmt.Sprintf("unsupported type %s", t))
+	}
+}
+
+// object returns the schema of a struct, following the rules of the
+// encoding/json package for the names of fields and embedded structs.
+func (g *generator) object(t reflect.Type) object {
+	properties := object{}
+	var required []string
+	g.properties(t, properties, &required)
+
+	schema := object{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+
+	return schema
+}
+
+// properties adds the properties of the fields of the struct.
+func (g *generator) properties(t reflect.Type, properties object, required *[]string) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" || !field.IsExported() {
+			continue
+		}
+
+		name, options, _ := strings.Cut(tag, ",")
+		if field.Anonymous && name == "" {
+			g.properties(field.Type, properties, required)
+			continue
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+
+		properties[name] = g.schema(field.Type)
+		if !strings.Contains(options, "omitempty") && field.Type.Kind() != reflect.Ptr {
+			*required = append(*required, name)
+		}
+	}
+}
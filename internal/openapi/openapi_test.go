@@ -0,0 +1,31 @@
+package openapi
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"testing"
+
+	"github.com/PeterEFinch/sudoku-solver/api"
+)
+
+var update = flag.Bool("update", false, "update the OpenAPI document of the api package")
+
+func TestGenerate(t *testing.T) {
+	document, err := Generate()
+	if err != nil {
+		t.Fatalf("failed to generate document: %v", err)
+	}
+
+	if *update {
+		err := os.WriteFile("../../api/openapi.json", document, 0644)
+		if err != nil {
+			t.Fatalf("failed to update document: %v", err)
+		}
+		return
+	}
+
+	if !bytes.Equal(document, api.OpenAPI) {
+		t.Fatal("api/openapi.json is out of date with the api package, run: go test ./internal/openapi -update")
+	}
+}
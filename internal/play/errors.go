@@ -0,0 +1,12 @@
+package play
+
+const (
+	ErrGivenCell  Error = "given_cell"
+	ErrFilledCell Error = "filled_cell"
+)
+
+type Error string
+
+func (e Error) Error() string {
+	return string(e)
+}
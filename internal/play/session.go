@@ -0,0 +1,152 @@
+// Package play tracks the progress of players solving a puzzle
+// interactively.
+package play
+
+import (
+	"context"
+
+	"github.com/PeterEFinch/sudoku-solver/internal/sudoku"
+)
+
+// Session is a puzzle being solved by a player. The given cells of the
+// puzzle cannot be changed, every other cell can hold a digit or a set
+// of pencil marks.
+//
+// A session is not safe for concurrent use.
+type Session struct {
+	solver *sudoku.Solver
+	puzzle sudoku.Grid
+	grid   sudoku.Grid
+
+	// marks holds the pencil marks of each cell as a bit set, where
+	// bit v is set if v is marked.
+	marks [sudoku.Size][sudoku.Size]uint16
+}
+
+// NewSession returns a session for the puzzle, which must not break
+// the standard rules.
+func NewSession(puzzle sudoku.Grid) (*Session, error) {
+	solver := sudoku.NewSolver(sudoku.StandardRules()...)
+	if len(solver.Conflicts(puzzle)) > 0 {
+		return nil, sudoku.ErrInvalidGrid
+	}
+
+	return &Session{
+		solver: solver,
+		puzzle: puzzle,
+		grid:   puzzle,
+	}, nil
+}
+
+// Puzzle returns the puzzle of the session.
+func (s *Session) Puzzle() sudoku.Grid {
+	return s.puzzle
+}
+
+// Grid returns the puzzle with the digits placed by the player.
+func (s *Session) Grid() sudoku.Grid {
+	return s.grid
+}
+
+// Marks returns the pencil marks of the cell in increasing order.
+func (s *Session) Marks(row, column int) []int {
+	var marks []int
+	for value := 1; value <= sudoku.Size; value++ {
+		if s.marks[row][column]&(1<<value) != 0 {
+			marks = append(marks, value)
+		}
+	}
+
+	return marks
+}
+
+// Place places the digit in the cell, replacing any digit or pencil
+// marks already there.
+func (s *Session) Place(row, column, value int) error {
+	err := s.checkChangeable(row, column)
+	if err != nil {
+		return err
+	}
+
+	// Sets a copy so the grid is unchanged if the value is invalid
+	grid := s.grid
+	_ = grid.Clear(row, column)
+	err = grid.Set(row, column, value)
+	if err != nil {
+		return err
+	}
+
+	s.grid = grid
+	s.marks[row][column] = 0
+	return nil
+}
+
+// Remove removes the digit placed in the cell, if any.
+func (s *Session) Remove(row, column int) error {
+	err := s.checkChangeable(row, column)
+	if err != nil {
+		return err
+	}
+
+	return s.grid.Clear(row, column)
+}
+
+// TogglePencilMark adds the pencil mark to the cell if it is not
+// already marked, otherwise it removes it. Only empty cells can be
+// marked.
+func (s *Session) TogglePencilMark(row, column, value int) error {
+	err := s.checkChangeable(row, column)
+	if err != nil {
+		return err
+	}
+
+	switch current, _ := s.grid.Get(row, column); {
+	case value < 1 || value > sudoku.Size:
+		return sudoku.ErrInvalidValue
+	case current != 0:
+		return ErrFilledCell
+	}
+
+	s.marks[row][column] ^= 1 << value
+	return nil
+}
+
+// Conflicts returns the filled cells which break the standard rules.
+func (s *Session) Conflicts() []sudoku.Cell {
+	return s.solver.Conflicts(s.grid)
+}
+
+// Completed returns true if every cell is filled without breaking the
+// standard rules.
+func (s *Session) Completed() bool {
+	for row := 0; row < sudoku.Size; row++ {
+		for column := 0; column < sudoku.Size; column++ {
+			if value, _ := s.grid.Get(row, column); value == 0 {
+				return false
+			}
+		}
+	}
+
+	return len(s.Conflicts()) == 0
+}
+
+// Hint returns the next entry the player could place. It fails with
+// sudoku.ErrNoSolution if the digits placed by the player cannot lead
+// to a solution.
+func (s *Session) Hint(ctx context.Context) (sudoku.Hint, error) {
+	return s.solver.Hint(ctx, s.grid)
+}
+
+// checkChangeable returns an error if the cell is outside the grid or
+// is given by the puzzle.
+func (s *Session) checkChangeable(row, column int) error {
+	given, err := s.puzzle.Get(row, column)
+	switch {
+	case err != nil:
+		return err
+	case given != 0:
+		return ErrGivenCell
+	}
+
+	return nil
+}
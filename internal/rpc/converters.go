@@ -0,0 +1,86 @@
+package rpc
+
+import (
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/PeterEFinch/sudoku-solver/api/sudokupb"
+	"github.com/PeterEFinch/sudoku-solver/internal/sudoku"
+)
+
+// toGrid converts the cells of the grid, in row-major order, into a
+// sudoku grid.
+func toGrid(grid *sudokupb.Grid) (sudoku.Grid, error) {
+	converted := sudoku.Grid{}
+	if len(grid.GetCells()) != sudoku.Size*sudoku.Size {
+		return converted, status.Errorf(codes.InvalidArgument, "invalid number of cells %d (expected %d)", len(grid.GetCells()), sudoku.Size*sudoku.Size)
+	}
+
+	for i, value := range grid.GetCells() {
+		row, column := i/sudoku.Size, i%sudoku.Size
+		if value < 0 || value > sudoku.Size {
+			return converted, status.Errorf(codes.InvalidArgument, "invalid entry: %d at position (%d, %d)", value, row, column)
+		}
+
+		if value > 0 {
+			_ = converted.Set(row, column, int(value))
+		}
+	}
+
+	return converted, nil
+}
+
+// fromGrid converts a sudoku grid into cells in row-major order.
+func fromGrid(grid sudoku.Grid) *sudokupb.Grid {
+	cells := make([]int32, 0, sudoku.Size*sudoku.Size)
+	for row := 0; row < sudoku.Size; row++ {
+		for column := 0; column < sudoku.Size; column++ {
+			value, _ := grid.Get(row, column)
+			cells = append(cells, int32(value))
+		}
+	}
+
+	return &sudokupb.Grid{Cells: cells}
+}
+
+func fromCell(cell sudoku.Cell) *sudokupb.Cell {
+	return &sudokupb.Cell{
+		Row:    int32(cell.Row),
+		Column: int32(cell.Column),
+	}
+}
+
+// fromStats converts the statistics of the solver, including the
+// detailed statistics if requested.
+func fromStats(stats sudoku.Stats, detailed bool) *sudokupb.SolveStats {
+	converted := &sudokupb.SolveStats{
+		Nodes:    int32(stats.Nodes),
+		MaxDepth: int32(stats.MaxDepth),
+	}
+	if !detailed {
+		return converted
+	}
+
+	converted.Guesses = int32(stats.Guesses)
+	converted.Deductions = make(map[string]int32, len(stats.Deductions))
+	for technique, count := range stats.Deductions {
+		converted.Deductions[string(technique)] = int32(count)
+	}
+
+	search := stats.Duration - stats.DeductionTime
+	if search < 0 {
+		search = 0
+	}
+
+	converted.DurationMs = milliseconds(stats.Duration)
+	converted.DeductionMs = milliseconds(stats.DeductionTime)
+	converted.SearchMs = milliseconds(search)
+	return converted
+}
+
+// milliseconds converts the duration into fractional milliseconds.
+func milliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
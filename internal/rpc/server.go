@@ -0,0 +1,332 @@
+// Package rpc implements the gRPC service of the server.
+package rpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/PeterEFinch/sudoku-solver/api/sudokupb"
+	"github.com/PeterEFinch/sudoku-solver/internal/auth"
+	"github.com/PeterEFinch/sudoku-solver/internal/sudoku"
+)
+
+// statusCodes maps the errors of the solver to gRPC status codes.
+var statusCodes = []struct {
+	err  error
+	code codes.Code
+}{
+	{sudoku.ErrInvalidGrid, codes.InvalidArgument},
+	{sudoku.ErrInvalidTechnique, codes.InvalidArgument},
+	{sudoku.ErrInvalidDifficulty, codes.InvalidArgument},
+	{sudoku.ErrGridCompleted, codes.FailedPrecondition},
+	{sudoku.ErrNoSolution, codes.FailedPrecondition},
+	{sudoku.ErrMultipleSolutions, codes.FailedPrecondition},
+	{sudoku.ErrGuessRequired, codes.FailedPrecondition},
+	{sudoku.ErrGenerationFailed, codes.ResourceExhausted},
+	{sudoku.ErrTimeout, codes.DeadlineExceeded},
+	{sudoku.ErrCancelled, codes.Canceled},
+	{auth.ErrQuotaExceeded, codes.ResourceExhausted},
+}
+
+// Option configures a Server.
+type Option func(server *Server)
+
+// WithTimeouts sets the timeout of solves without a timeout_ms and caps
+// the timeout of every solve, where zero means no timeout or cap.
+func WithTimeouts(timeout, max time.Duration) Option {
+	return func(server *Server) {
+		server.timeout = timeout
+		server.maxTimeout = max
+	}
+}
+
+// WithMaxSolutions caps the number of solutions returned by unary
+// solves, which collect every solution in memory. Streamed solves are
+// not capped. Zero means no cap.
+func WithMaxSolutions(n int) Option {
+	return func(server *Server) {
+		server.maxSolutions = n
+	}
+}
+
+// Server implements the Sudoku gRPC service using the standard rules.
+type Server struct {
+	sudokupb.UnimplementedSudokuServer
+
+	timeout      time.Duration
+	maxTimeout   time.Duration
+	maxSolutions int
+}
+
+// NewServer returns a new gRPC server configured by the options.
+func NewServer(options ...Option) *Server {
+	server := &Server{}
+	for _, option := range options {
+		option(server)
+	}
+
+	return server
+}
+
+func (s *Server) Solve(ctx context.Context, request *sudokupb.SolveRequest) (*sudokupb.SolveResponse, error) {
+	response := &sudokupb.SolveResponse{}
+	summary, err := s.solve(ctx, request, s.maxSolutions, func(solution sudoku.Grid) bool {
+		response.Solutions = append(response.Solutions, fromGrid(solution))
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	response.Summary = summary
+	return response, nil
+}
+
+func (s *Server) SolveStream(request *sudokupb.SolveRequest, stream sudokupb.Sudoku_SolveStreamServer) error {
+	var sendErr error
+	summary, err := s.solve(stream.Context(), request, 0, func(solution sudoku.Grid) bool {
+		sendErr = stream.Send(&sudokupb.SolveStreamResponse{
+			Event: &sudokupb.SolveStreamResponse_Solution{Solution: fromGrid(solution)},
+		})
+		return sendErr == nil
+	})
+	switch {
+	case sendErr != nil:
+		return sendErr
+	case err != nil:
+		return err
+	}
+
+	return stream.Send(&sudokupb.SolveStreamResponse{
+		Event: &sudokupb.SolveStreamResponse_Summary{Summary: summary},
+	})
+}
+
+func (s *Server) Validate(_ context.Context, request *sudokupb.ValidateRequest) (*sudokupb.ValidateResponse, error) {
+	grid, err := toGrid(request.Grid)
+	if err != nil {
+		return nil, err
+	}
+
+	conflicts := sudoku.NewSolver(sudoku.StandardRules()...).Conflicts(grid)
+	response := &sudokupb.ValidateResponse{Valid: len(conflicts) == 0}
+	for _, cell := range conflicts {
+		response.Conflicts = append(response.Conflicts, fromCell(cell))
+	}
+
+	return response, nil
+}
+
+func (s *Server) Hint(ctx context.Context, request *sudokupb.HintRequest) (*sudokupb.HintResponse, error) {
+	grid, err := toGrid(request.Grid)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := s.withTimeout(ctx, 0)
+	defer cancel()
+
+	hint, err := sudoku.NewSolver(sudoku.StandardRules()...).Hint(ctx, grid)
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	return &sudokupb.HintResponse{
+		Cell:      fromCell(hint.Cell),
+		Value:     int32(hint.Value),
+		Technique: string(hint.Technique),
+	}, nil
+}
+
+func (s *Server) Grade(ctx context.Context, request *sudokupb.GradeRequest) (*sudokupb.GradeResponse, error) {
+	grid, err := toGrid(request.Grid)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := s.withTimeout(ctx, 0)
+	defer cancel()
+
+	ctx, settle, err := auth.Meter(ctx)
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	grade, err := sudoku.StandardGrade(ctx, grid)
+	if err = settle(err); err != nil {
+		return nil, statusError(err)
+	}
+
+	return &sudokupb.GradeResponse{
+		Difficulty: string(grade.Difficulty),
+		Stats:      fromStats(grade.Stats, true),
+	}, nil
+}
+
+func (s *Server) Generate(ctx context.Context, request *sudokupb.GenerateRequest) (*sudokupb.GenerateResponse, error) {
+	difficulty, err := sudoku.ParseDifficulty(request.Difficulty)
+	if err != nil {
+		return nil, statusError(fmt.Errorf("invalid difficulty %q: %w", request.Difficulty, err))
+	}
+
+	seed := request.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+
+	ctx, cancel := s.withTimeout(ctx, 0)
+	defer cancel()
+
+	ctx, settle, err := auth.Meter(ctx)
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	puzzle, solution, err := sudoku.Generate(ctx, difficulty, rand.New(rand.NewSource(seed)))
+	if err = settle(err); err != nil {
+		return nil, statusError(err)
+	}
+
+	return &sudokupb.GenerateResponse{
+		Puzzle:   fromGrid(puzzle),
+		Solution: fromGrid(solution),
+		Seed:     seed,
+	}, nil
+}
+
+// solve converts the request and streams the solutions of its grid,
+// finding at most maxSolutions solutions unless it is zero. A solve
+// stopped by its timeout or interrupted, e.g. by the server shutting
+// down, is not an error: its summary is not completed and gives the
+// reason it stopped, so the solutions found so far are kept.
+func (s *Server) solve(ctx context.Context, request *sudokupb.SolveRequest, maxSolutions int, yield func(solution sudoku.Grid) bool) (*sudokupb.SolveSummary, error) {
+	grid, err := toGrid(request.Grid)
+	if err != nil {
+		return nil, err
+	}
+
+	options, err := solveOptions(request)
+	if err != nil {
+		return nil, err
+	}
+
+	if maxSolutions > 0 && (request.MaxSolutions == 0 || int(request.MaxSolutions) > maxSolutions) {
+		options = append(options, sudoku.WithMaxSolutions(maxSolutions))
+	}
+
+	ctx, cancel := s.withTimeout(ctx, request.TimeoutMs)
+	defer cancel()
+
+	ctx, settle, err := auth.Meter(ctx)
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	count := 0
+	summary, err := sudoku.StandardStream(ctx, grid, func(solution sudoku.Grid) bool {
+		count++
+		return yield(solution)
+	}, options...)
+	err = settle(err)
+	if err != nil && !errors.Is(err, sudoku.ErrTimeout) && !errors.Is(err, sudoku.ErrCancelled) {
+		return nil, statusError(err)
+	}
+
+	return &sudokupb.SolveSummary{
+		Completed: summary.Completed(),
+		Count:     int32(count),
+		StoppedBy: string(summary.Limit),
+		Stats:     fromStats(summary.Stats, request.Stats),
+	}, nil
+}
+
+// withTimeout returns a copy of the context with the timeout of a call,
+// which is timeoutMs if it is set and the timeout of the server
+// otherwise, capped by the max timeout of the server.
+func (s *Server) withTimeout(ctx context.Context, timeoutMs int32) (context.Context, context.CancelFunc) {
+	timeout := time.Duration(timeoutMs) * time.Millisecond
+	if timeout == 0 {
+		timeout = s.timeout
+	}
+	if s.maxTimeout > 0 && (timeout == 0 || timeout > s.maxTimeout) {
+		timeout = s.maxTimeout
+	}
+
+	if timeout == 0 {
+		return context.WithCancel(ctx)
+	}
+
+	return context.WithTimeout(ctx, timeout)
+}
+
+// solveOptions converts the fields of the request into options for the
+// sudoku solver.
+func solveOptions(request *sudokupb.SolveRequest) ([]sudoku.Option, error) {
+	switch {
+	case request.MaxSolutions < 0:
+		return nil, status.Errorf(codes.InvalidArgument, "invalid max_solutions %d", request.MaxSolutions)
+	case request.MaxNodes < 0:
+		return nil, status.Errorf(codes.InvalidArgument, "invalid max_nodes %d", request.MaxNodes)
+	case request.MaxDepth < 0:
+		return nil, status.Errorf(codes.InvalidArgument, "invalid max_depth %d", request.MaxDepth)
+	}
+
+	options := []sudoku.Option{
+		sudoku.WithMaxSolutions(int(request.MaxSolutions)),
+		sudoku.WithMaxNodes(int(request.MaxNodes)),
+		sudoku.WithMaxDepth(int(request.MaxDepth)),
+	}
+
+	if len(request.Techniques) > 0 {
+		techniques, err := parseTechniques(request.Techniques)
+		if err != nil {
+			return nil, err
+		}
+		options = append(options, sudoku.WithTechniques(techniques...))
+	}
+
+	if len(request.DisabledTechniques) > 0 {
+		techniques, err := parseTechniques(request.DisabledTechniques)
+		if err != nil {
+			return nil, err
+		}
+		options = append(options, sudoku.WithoutTechniques(techniques...))
+	}
+
+	if request.LogicOnly {
+		options = append(options, sudoku.WithoutGuessing())
+	}
+
+	return options, nil
+}
+
+// parseTechniques converts the names into techniques.
+func parseTechniques(names []string) ([]sudoku.Technique, error) {
+	techniques := make([]sudoku.Technique, len(names))
+	for i, name := range names {
+		technique, err := sudoku.ParseTechnique(name)
+		if err != nil {
+			return nil, statusError(fmt.Errorf("invalid technique %q: %w", name, err))
+		}
+		techniques[i] = technique
+	}
+
+	return techniques, nil
+}
+
+// statusError converts an error of the solver into a gRPC status error.
+func statusError(err error) error {
+	for _, s := range statusCodes {
+		if errors.Is(err, s.err) {
+			return status.Error(s.code, err.Error())
+		}
+	}
+
+	return status.Error(codes.Internal, err.Error())
+}
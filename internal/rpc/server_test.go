@@ -0,0 +1,252 @@
+package rpc
+
+import (
+	"context"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/PeterEFinch/sudoku-solver/api/sudokupb"
+	"github.com/PeterEFinch/sudoku-solver/internal/auth"
+	"github.com/PeterEFinch/sudoku-solver/internal/middleware"
+)
+
+var difficultCells = []int32{
+	8, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 3, 6, 0, 0, 0, 0, 0,
+	0, 7, 0, 0, 9, 0, 2, 0, 0,
+	0, 5, 0, 0, 0, 7, 0, 0, 0,
+	0, 0, 0, 0, 4, 5, 7, 0, 0,
+	0, 0, 0, 1, 0, 0, 0, 3, 0,
+	0, 0, 1, 0, 0, 0, 0, 6, 8,
+	0, 0, 8, 5, 0, 0, 0, 1, 0,
+	0, 9, 0, 0, 0, 0, 4, 0, 0,
+}
+
+// newClient returns a client of the service listening in memory, or of
+// a default service if it is nil, served with the options.
+func newClient(t *testing.T, service *Server, options ...grpc.ServerOption) sudokupb.SudokuClient {
+	if service == nil {
+		service = NewServer()
+	}
+
+	listener := bufconn.Listen(1 << 20)
+	server := grpc.NewServer(options...)
+	sudokupb.RegisterSudokuServer(server, service)
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("failed to dial: %v", err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return sudokupb.NewSudokuClient(conn)
+}
+
+func TestServer_SolveStream(t *testing.T) {
+	client := newClient(t, nil)
+
+	stream, err := client.SolveStream(context.Background(), &sudokupb.SolveRequest{
+		Grid:      &sudokupb.Grid{Cells: difficultCells},
+		TimeoutMs: 5000,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var solutions int
+	var summary *sudokupb.SolveSummary
+	for {
+		response, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if response.GetSolution() != nil {
+			solutions++
+		}
+		if response.GetSummary() != nil {
+			summary = response.GetSummary()
+		}
+	}
+
+	if solutions != 1 {
+		t.Errorf("expected 1 solution, got %d", solutions)
+	}
+	if summary == nil || !summary.Completed {
+		t.Errorf("expected a completed summary, got %v", summary)
+	}
+}
+
+func TestServer_Errors(t *testing.T) {
+	client := newClient(t, nil)
+
+	invalid := make([]int32, len(difficultCells))
+	invalid[0], invalid[1] = 8, 8
+
+	tests := []struct {
+		name string
+		call func() error
+		code codes.Code
+	}{
+		{"malformed grid", func() error {
+			_, err := client.Solve(context.Background(), &sudokupb.SolveRequest{Grid: &sudokupb.Grid{Cells: []int32{1}}})
+			return err
+		}, codes.InvalidArgument},
+		{"invalid grid", func() error {
+			_, err := client.Solve(context.Background(), &sudokupb.SolveRequest{Grid: &sudokupb.Grid{Cells: invalid}})
+			return err
+		}, codes.InvalidArgument},
+		{"invalid difficulty", func() error {
+			_, err := client.Generate(context.Background(), &sudokupb.GenerateRequest{Difficulty: "impossible"})
+			return err
+		}, codes.InvalidArgument},
+		{"validate", func() error {
+			response, err := client.Validate(context.Background(), &sudokupb.ValidateRequest{Grid: &sudokupb.Grid{Cells: invalid}})
+			if err == nil && (response.Valid || len(response.Conflicts) != 2) {
+				t.Errorf("expected 2 conflicts, got %v", response.Conflicts)
+			}
+			return err
+		}, codes.OK},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if code := status.Code(test.call()); code != test.code {
+				t.Errorf("expected code %v, got %v", test.code, code)
+			}
+		})
+	}
+}
+
+func TestServer_Limits(t *testing.T) {
+	empty := &sudokupb.Grid{Cells: make([]int32, len(difficultCells))}
+
+	t.Run("max solutions", func(t *testing.T) {
+		client := newClient(t, NewServer(WithTimeouts(time.Minute, time.Minute), WithMaxSolutions(5)))
+		response, err := client.Solve(context.Background(), &sudokupb.SolveRequest{Grid: empty})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(response.Solutions) != 5 || response.Summary.StoppedBy != "max_solutions" {
+			t.Fatalf("expected 5 solutions stopped by max_solutions, got %d stopped by %q", len(response.Solutions), response.Summary.StoppedBy)
+		}
+	})
+
+	// Streams every solution until the max timeout caps the call
+	t.Run("max timeout", func(t *testing.T) {
+		client := newClient(t, NewServer(WithTimeouts(time.Minute, 200*time.Millisecond)))
+		start := time.Now()
+		stream, err := client.SolveStream(context.Background(), &sudokupb.SolveRequest{Grid: empty, TimeoutMs: 60000})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		var summary *sudokupb.SolveSummary
+		for err == nil {
+			var message *sudokupb.SolveStreamResponse
+			message, err = stream.Recv()
+			if message.GetSummary() != nil {
+				summary = message.GetSummary()
+			}
+		}
+		if elapsed := time.Since(start); elapsed > 10*time.Second || err != io.EOF {
+			t.Fatalf("expected the stream to be stopped by the max timeout, took %v: %v", elapsed, err)
+		}
+		if summary == nil || summary.Completed || summary.StoppedBy != "timeout" {
+			t.Fatalf("expected a summary stopped by the timeout, got %+v", summary)
+		}
+	})
+
+	// Returns the solutions found before the default timeout
+	t.Run("default timeout", func(t *testing.T) {
+		client := newClient(t, NewServer(WithTimeouts(200*time.Millisecond, 0)))
+		response, err := client.Solve(context.Background(), &sudokupb.SolveRequest{Grid: empty})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if response.Summary.Completed || response.Summary.StoppedBy != "timeout" {
+			t.Fatalf("expected partial solutions stopped by the timeout, got %d stopped by %q", len(response.Solutions), response.Summary.StoppedBy)
+		}
+	})
+}
+
+func TestServer_Interceptors(t *testing.T) {
+	store, err := auth.NewStore("", []string{auth.Hash("key")})
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+
+	rateLimiter := middleware.NewRateLimiter(0.001, 2)
+	client := newClient(t, nil,
+		grpc.ChainUnaryInterceptor(auth.UnaryServerInterceptor(store), rateLimiter.UnaryServerInterceptor),
+		grpc.ChainStreamInterceptor(auth.StreamServerInterceptor(store), rateLimiter.StreamServerInterceptor),
+	)
+
+	request := &sudokupb.ValidateRequest{Grid: &sudokupb.Grid{Cells: difficultCells}}
+	tests := []struct {
+		name     string
+		metadata []string
+		code     codes.Code
+	}{
+		{"missing key", nil, codes.Unauthenticated},
+		{"invalid key", []string{"x-api-key", "other"}, codes.Unauthenticated},
+		{"key", []string{"x-api-key", "key"}, codes.OK},
+		{"bearer token", []string{"authorization", "Bearer key"}, codes.OK},
+		{"rate limited", []string{"x-api-key", "key"}, codes.ResourceExhausted},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := metadata.AppendToOutgoingContext(context.Background(), tt.metadata...)
+			_, err := client.Validate(ctx, request)
+			if code := status.Code(err); code != tt.code {
+				t.Fatalf("expected code %v, got %v: %v", tt.code, code, err)
+			}
+		})
+	}
+}
+
+func TestServer_Quota(t *testing.T) {
+	store, err := auth.NewStore("", nil)
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+
+	secret, _, err := store.Create("client", 1)
+	if err != nil {
+		t.Fatalf("failed to create key: %v", err)
+	}
+
+	client := newClient(t, nil,
+		grpc.ChainUnaryInterceptor(auth.UnaryServerInterceptor(store)),
+		grpc.ChainStreamInterceptor(auth.StreamServerInterceptor(store)),
+	)
+
+	// The empty grid has more solutions than can be found within the
+	// quota, which then rejects further calls
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "x-api-key", secret)
+	for i := 0; i < 2; i++ {
+		_, err := client.Solve(ctx, &sudokupb.SolveRequest{Grid: &sudokupb.Grid{Cells: make([]int32, 81)}})
+		if code := status.Code(err); code != codes.ResourceExhausted {
+			t.Fatalf("expected code %v from call %d, got %v: %v", codes.ResourceExhausted, i, code, err)
+		}
+	}
+}
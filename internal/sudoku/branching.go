@@ -0,0 +1,179 @@
+package sudoku
+
+// Brancher chooses where the solver guesses once the deductions
+// have been exhausted. Implementations outside this package may be
+// given to the solver using WithBrancher.
+type Brancher interface {
+	// Branches returns the entries to try in turn, given the grid, the
+	// candidates of its empty cells and the units of the rules of the
+	// solver. Every solution of the grid must contain exactly one of
+	// the entries, so returning no entries marks the grid as having no
+	// solutions. The entries are tried in the order given, which
+	// determines the order of the solutions.
+	Branches(grid Grid, candidates Candidates, units []Unit) []Entry
+}
+
+// Entry is a value placed in a cell of a grid.
+type Entry struct {
+	Cell
+	Value int
+}
+
+// Unit is a group of cells which must contain every value exactly
+// once, e.g. a row, column or square.
+type Unit [Size]Cell
+
+// Candidates are the values which remain possible in each cell of a
+// grid once the deductions have been exhausted.
+type Candidates struct {
+	possibilities *possibilities
+}
+
+// Count returns the number of values possible in the cell.
+func (c Candidates) Count(cell Cell) int {
+	return len(c.possibilities[cell.Row][cell.Column])
+}
+
+// Has returns true if the value is possible in the cell.
+func (c Candidates) Has(cell Cell, value int) bool {
+	_, ok := c.possibilities[cell.Row][cell.Column][value]
+	return ok
+}
+
+// Values returns the values possible in the cell in increasing order.
+func (c Candidates) Values(cell Cell) []int {
+	values := make([]int, 0, c.Count(cell))
+	for value := 1; value <= Size; value++ {
+		if c.Has(cell, value) {
+			values = append(values, value)
+		}
+	}
+
+	return values
+}
+
+// region First Empty Cell
+
+// FirstEmptyCell branches on the first empty cell in row-major
+// order, trying every value regardless of the possibilities.
+func FirstEmptyCell() Brancher {
+	return firstEmptyCell{}
+}
+
+type firstEmptyCell struct{}
+
+func (firstEmptyCell) Branches(grid Grid, _ Candidates, _ []Unit) []Entry {
+	for row := 0; row < Size; row++ {
+		for column := 0; column < Size; column++ {
+			if grid.isCellFilled(row, column) {
+				continue
+			}
+
+			entries := make([]Entry, 0, Size)
+			for value := 1; value <= Size; value++ {
+				entries = append(entries, Entry{Cell: Cell{Row: row, Column: column}, Value: value})
+			}
+			return entries
+		}
+	}
+
+	return nil
+}
+
+// endregion
+
+// region Minimum Remaining Values
+
+// MinimumRemainingValues branches on the empty cell with the fewest
+// possibilities, trying only those possibilities.
+func MinimumRemainingValues() Brancher {
+	return minimumRemainingValues{}
+}
+
+type minimumRemainingValues struct{}
+
+func (minimumRemainingValues) Branches(grid Grid, candidates Candidates, _ []Unit) []Entry {
+	best := Size + 1
+	var bestCell *Cell
+	for row := 0; row < Size; row++ {
+		for column := 0; column < Size; column++ {
+			if grid.isCellFilled(row, column) {
+				continue
+			}
+
+			cell := Cell{Row: row, Column: column}
+			if count := candidates.Count(cell); count < best {
+				best = count
+				bestCell = &cell
+			}
+		}
+	}
+
+	if bestCell == nil {
+		return nil
+	}
+
+	entries := make([]Entry, 0, best)
+	for _, value := range candidates.Values(*bestCell) {
+		entries = append(entries, Entry{Cell: *bestCell, Value: value})
+	}
+
+	return entries
+}
+
+// endregion
+
+// region Most Constrained Digit
+
+// MostConstrainedDigit branches on the value and unit (e.g. row,
+// column or square) with the fewest cells where the value is
+// possible, trying each of those cells. It falls back to
+// MinimumRemainingValues when the rules do not define any units.
+func MostConstrainedDigit() Brancher {
+	return mostConstrainedDigit{}
+}
+
+type mostConstrainedDigit struct{}
+
+func (mostConstrainedDigit) Branches(grid Grid, candidates Candidates, units []Unit) []Entry {
+	if len(units) == 0 {
+		return minimumRemainingValues{}.Branches(grid, candidates, units)
+	}
+
+	var best []Entry
+	found := false
+	for _, unit := range units {
+		for value := 1; value <= Size; value++ {
+			entries, placed := digitCandidates(grid, candidates, unit, value)
+			if placed {
+				continue
+			}
+
+			if !found || len(entries) < len(best) {
+				best = entries
+				found = true
+			}
+		}
+	}
+
+	return best
+}
+
+// digitCandidates returns the cells of the unit where the value is
+// possible and whether the value has already been placed in the unit.
+func digitCandidates(grid Grid, candidates Candidates, unit Unit, value int) ([]Entry, bool) {
+	var entries []Entry
+	for _, cell := range unit {
+		if grid.values[cell.Row][cell.Column] == value {
+			return nil, true
+		}
+
+		if grid.isCellEmpty(cell.Row, cell.Column) && candidates.Has(cell, value) {
+			entries = append(entries, Entry{Cell: cell, Value: value})
+		}
+	}
+
+	return entries, false
+}
+
+// endregion
@@ -0,0 +1,299 @@
+package sudoku
+
+// Transform maps a grid onto an equivalent grid, i.e. one with the same
+// number of solutions, which are mapped by the same transform. It is
+// composed of transposing the grid, permuting its rows and columns
+// while keeping them within their bands and stacks, permuting the bands
+// and stacks, and relabelling the digits. Rotations and reflections are
+// combinations of these.
+type Transform struct {
+	// Transpose swaps the rows and columns before they are permuted.
+	Transpose bool
+
+	// Rows are the rows, after transposing, which become each row.
+	Rows [Size]int
+
+	// Columns are the columns, after transposing, which become each
+	// column.
+	Columns [Size]int
+
+	// Digits are the digits each digit is relabelled to, where the zero
+	// of empty squares is always mapped to itself.
+	Digits [Size + 1]int
+}
+
+// IdentityTransform returns the transform which maps every grid onto
+// itself.
+func IdentityTransform() Transform {
+	t := Transform{}
+	for i := 0; i < Size; i++ {
+		t.Rows[i], t.Columns[i] = i, i
+	}
+	for digit := range t.Digits {
+		t.Digits[digit] = digit
+	}
+
+	return t
+}
+
+// Apply returns the grid mapped by the transform.
+func (t Transform) Apply(grid Grid) Grid {
+	source := grid
+	if t.Transpose {
+		source = transposed(grid)
+	}
+
+	mapped := Grid{}
+	for row := 0; row < Size; row++ {
+		for column := 0; column < Size; column++ {
+			mapped.values[row][column] = t.Digits[source.values[t.Rows[row]][t.Columns[column]]]
+		}
+	}
+
+	return mapped
+}
+
+// Inverse returns the transform which maps the grids given by the
+// transform back onto the original grids.
+func (t Transform) Inverse() Transform {
+	inverse := Transform{Transpose: t.Transpose}
+	for i := 0; i < Size; i++ {
+		inverse.Rows[t.Rows[i]] = i
+		inverse.Columns[t.Columns[i]] = i
+	}
+	for digit, relabelled := range t.Digits {
+		inverse.Digits[relabelled] = digit
+	}
+
+	// Undoing the permutations of a transposed grid permutes the
+	// original columns by the rows and vice versa
+	if t.Transpose {
+		inverse.Rows, inverse.Columns = inverse.Columns, inverse.Rows
+	}
+
+	return inverse
+}
+
+// Then returns the transform applying the transform followed by next.
+func (t Transform) Then(next Transform) Transform {
+	composed := Transform{Transpose: t.Transpose != next.Transpose}
+	for i := 0; i < Size; i++ {
+		if next.Transpose {
+			composed.Rows[i] = t.Columns[next.Rows[i]]
+			composed.Columns[i] = t.Rows[next.Columns[i]]
+		} else {
+			composed.Rows[i] = t.Rows[next.Rows[i]]
+			composed.Columns[i] = t.Columns[next.Columns[i]]
+		}
+	}
+	for digit := range composed.Digits {
+		composed.Digits[digit] = next.Digits[t.Digits[digit]]
+	}
+
+	return composed
+}
+
+// Canonicalize returns the canonical form of the grid, which is the
+// same for every equivalent grid, along with the transform mapping the
+// grid onto it. The canonical form is the equivalent grid which is
+// lexicographically smallest when read row by row, where empty squares
+// are smallest.
+func Canonicalize(grid Grid) (Grid, Transform) {
+	c := &canonicalizer{}
+	for _, transpose := range []bool{false, true} {
+		source := grid
+		if transpose {
+			source = transposed(grid)
+		}
+
+		for _, columns := range bandPermutations {
+			for row := 0; row < Size; row++ {
+				for column := 0; column < Size; column++ {
+					c.rows[row][column] = source.values[row][columns[column]]
+				}
+			}
+
+			c.transpose, c.columns = transpose, columns
+			c.search(0, [Size + 1]int{}, 0, !c.found)
+		}
+	}
+
+	canonical := Grid{values: c.best}
+	return canonical, c.transform
+}
+
+// Equivalent returns true if the grids are equivalent, along with the
+// transform mapping the first grid onto the second.
+func Equivalent(a, b Grid) (Transform, bool) {
+	canonicalA, transformA := Canonicalize(a)
+	canonicalB, transformB := Canonicalize(b)
+	if canonicalA != canonicalB {
+		return Transform{}, false
+	}
+
+	return transformA.Then(transformB.Inverse()), true
+}
+
+// bandPermutations are the permutations of the rows, or columns, which
+// keep them within their bands, or stacks.
+var bandPermutations = func() [][Size]int {
+	orders := [][3]int{{0, 1, 2}, {0, 2, 1}, {1, 0, 2}, {1, 2, 0}, {2, 0, 1}, {2, 1, 0}}
+	permutations := make([][Size]int, 0, 6*6*6*6)
+	for _, bands := range orders {
+		for _, first := range orders {
+			for _, second := range orders {
+				for _, third := range orders {
+					var permutation [Size]int
+					for i, within := range [3][3]int{first, second, third} {
+						for j := 0; j < 3; j++ {
+							permutation[3*i+j] = 3*bands[i] + within[j]
+						}
+					}
+					permutations = append(permutations, permutation)
+				}
+			}
+		}
+	}
+
+	return permutations
+}()
+
+// canonicalizer searches for the smallest equivalent grid. For each
+// transposition and permutation of the columns, the rows are chosen one
+// at a time, relabelling the digits in the order they first appear,
+// and abandoning any choice which is larger than the smallest grid so
+// far.
+type canonicalizer struct {
+	// rows are the rows of the grid with the current transposition
+	// and permutation of the columns.
+	rows      [Size][Size]int
+	transpose bool
+	columns   [Size]int
+
+	// order are the rows chosen so far, which are marked as used.
+	order [Size]int
+	used  [Size]bool
+
+	found     bool
+	best      [Size][Size]int
+	transform Transform
+}
+
+// search chooses the row at the index, given the relabelling of the
+// digits in the rows chosen so far, where labels is the number of
+// digits relabelled. If less is true, the rows chosen so far are
+// smaller than those of the smallest grid, otherwise they are the same.
+func (c *canonicalizer) search(index int, digits [Size + 1]int, labels int, less bool) {
+	if index == Size {
+		if less {
+			c.record(digits)
+		}
+		return
+	}
+
+	// The first row of a band may be any row of an unused band,
+	// the others must be in the same band as it
+	band := c.order[index-index%3] / 3
+	first, last := 3*band, 3*band+3
+	if index%3 == 0 {
+		first, last = 0, Size
+	}
+
+	for row := first; row < last; row++ {
+		if c.used[row] || index%3 == 0 && c.bandUsed(row/3) || c.duplicate(row) {
+			continue
+		}
+
+		relabelled, relabels := digits, labels
+		comparison := 0
+		if less {
+			comparison = -1
+		}
+
+		for column, value := range c.rows[row] {
+			if value != 0 {
+				if relabelled[value] == 0 {
+					relabels++
+					relabelled[value] = relabels
+				}
+				value = relabelled[value]
+			}
+
+			if comparison == 0 && value != c.best[index][column] {
+				comparison = 1
+				if value < c.best[index][column] {
+					comparison = -1
+				}
+			}
+			if comparison > 0 {
+				break
+			}
+		}
+		if comparison > 0 {
+			continue
+		}
+
+		c.order[index], c.used[row] = row, true
+		c.search(index+1, relabelled, relabels, comparison < 0)
+		c.used[row] = false
+
+		// Any smaller grid found has the same rows so far, which
+		// are therefore no longer smaller
+		less = false
+	}
+}
+
+// bandUsed returns true if a row of the band has been chosen.
+func (c *canonicalizer) bandUsed(band int) bool {
+	return c.used[3*band] || c.used[3*band+1] || c.used[3*band+2]
+}
+
+// duplicate returns true if an unused row before the row in its band
+// is the same, in which case choosing either gives the same grids.
+func (c *canonicalizer) duplicate(row int) bool {
+	for other := 3 * (row / 3); other < row; other++ {
+		if !c.used[other] && c.rows[other] == c.rows[row] {
+			return true
+		}
+	}
+
+	return false
+}
+
+// record records the chosen rows as the smallest grid.
+func (c *canonicalizer) record(digits [Size + 1]int) {
+	// Digits which do not appear are relabelled in order after those
+	// which do, so the transform is a relabelling of every digit
+	labels := 0
+	for _, label := range digits {
+		if label > labels {
+			labels = label
+		}
+	}
+	for digit := 1; digit <= Size; digit++ {
+		if digits[digit] == 0 {
+			labels++
+			digits[digit] = labels
+		}
+	}
+
+	c.found = true
+	c.transform = Transform{Transpose: c.transpose, Columns: c.columns, Rows: c.order, Digits: digits}
+	for index, row := range c.order {
+		for column, value := range c.rows[row] {
+			c.best[index][column] = digits[value]
+		}
+	}
+}
+
+// transposed returns the grid with its rows and columns swapped.
+func transposed(grid Grid) Grid {
+	swapped := Grid{}
+	for row := 0; row < Size; row++ {
+		for column := 0; column < Size; column++ {
+			swapped.values[column][row] = grid.values[row][column]
+		}
+	}
+
+	return swapped
+}
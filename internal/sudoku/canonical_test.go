@@ -0,0 +1,129 @@
+package sudoku
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+)
+
+func TestCanonicalize(t *testing.T) {
+	solved, _ := StandardSolve(context.Background(), DifficultExampleGrid())
+	single := Grid{}
+	single.values[4][4] = 5
+
+	tests := []struct {
+		name string
+		grid Grid
+	}{
+		{"puzzle", DifficultExampleGrid()},
+		{"solved", solved.Solutions[0]},
+		{"single clue", single},
+		{"empty", Grid{}},
+	}
+
+	rng := rand.New(rand.NewSource(1))
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			canonical, transform := Canonicalize(tt.grid)
+			if mapped := transform.Apply(tt.grid); mapped != canonical {
+				t.Fatalf("expected the transform to map the grid onto\n%s\ngot\n%s", canonical.String(), mapped.String())
+			}
+
+			if again, _ := Canonicalize(canonical); again != canonical {
+				t.Fatalf("expected the canonical form to be its own canonical form, got\n%s", again.String())
+			}
+
+			equivalents := []Grid{rotated(tt.grid)}
+			for i := 0; i < 20; i++ {
+				equivalents = append(equivalents, randomTransform(rng).Apply(tt.grid))
+			}
+
+			for _, equivalent := range equivalents {
+				if other, _ := Canonicalize(equivalent); other != canonical {
+					t.Fatalf("expected equivalent grid\n%s\nto have canonical form\n%s\ngot\n%s", equivalent.String(), canonical.String(), other.String())
+				}
+			}
+		})
+	}
+}
+
+func TestEquivalent(t *testing.T) {
+	rng := rand.New(rand.NewSource(2))
+	grid := DifficultExampleGrid()
+	cleared := grid.clone()
+	_ = cleared.Clear(0, 0)
+	moved := cleared.clone()
+	_ = moved.Set(0, 1, 8)
+
+	tests := []struct {
+		name       string
+		a, b       Grid
+		equivalent bool
+	}{
+		{"same", grid, grid, true},
+		{"transformed", grid, randomTransform(rng).Apply(grid), true},
+		{"rotated", grid, rotated(rotated(rotated(grid))), true},
+		{"different clues", grid, cleared, false},
+		{"same number of clues", grid, moved, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transform, equivalent := Equivalent(tt.a, tt.b)
+			if equivalent != tt.equivalent {
+				t.Fatalf("expected equivalent to be %t, got %t", tt.equivalent, equivalent)
+			}
+
+			if mapped := transform.Apply(tt.a); equivalent && mapped != tt.b {
+				t.Fatalf("expected the transform to map the first grid onto the second, got\n%s", mapped.String())
+			}
+		})
+	}
+}
+
+func TestTransform(t *testing.T) {
+	rng := rand.New(rand.NewSource(3))
+	grid := DifficultExampleGrid()
+
+	if identity := IdentityTransform().Apply(grid); identity != grid {
+		t.Fatalf("expected the identity to keep the grid, got\n%s", identity.String())
+	}
+
+	for i := 0; i < 20; i++ {
+		first, second := randomTransform(rng), randomTransform(rng)
+		if restored := first.Inverse().Apply(first.Apply(grid)); restored != grid {
+			t.Fatalf("expected the inverse of %+v to restore the grid, got\n%s", first, restored.String())
+		}
+
+		composed, expected := first.Then(second).Apply(grid), second.Apply(first.Apply(grid))
+		if composed != expected {
+			t.Fatalf("expected %+v then %+v to give\n%s\ngot\n%s", first, second, expected.String(), composed.String())
+		}
+	}
+}
+
+// randomTransform returns a random transform.
+func randomTransform(rng *rand.Rand) Transform {
+	t := Transform{
+		Transpose: rng.Intn(2) == 1,
+		Rows:      bandPermutations[rng.Intn(len(bandPermutations))],
+		Columns:   bandPermutations[rng.Intn(len(bandPermutations))],
+	}
+	for digit, relabelled := range rng.Perm(Size) {
+		t.Digits[digit+1] = relabelled + 1
+	}
+
+	return t
+}
+
+// rotated returns the grid rotated clockwise by a quarter turn.
+func rotated(grid Grid) Grid {
+	rotation := Grid{}
+	for row := 0; row < Size; row++ {
+		for column := 0; column < Size; column++ {
+			rotation.values[column][Size-1-row] = grid.values[row][column]
+		}
+	}
+
+	return rotation
+}
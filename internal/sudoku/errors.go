@@ -1,13 +1,23 @@
 package sudoku
 
 const (
-	ErrTimeout Error = "timeout"
-	//ErrGuessRequired Error = "guess_required"
+	ErrTimeout       Error = "timeout"
+	ErrCancelled     Error = "cancelled"
+	ErrGuessRequired Error = "guess_required"
+	ErrInvalidGrid   Error = "invalid_grid"
+	ErrNoSolution    Error = "no_solution"
 
 	ErrInvalidValue     Error = "invalid_value"
 	ErrInvalidRow       Error = "invalid_row"
 	ErrInvalidColumn    Error = "invalid_column"
 	ErrSquareAlreadySet Error = "square_already_set"
+
+	ErrInvalidTechnique  Error = "invalid_technique"
+	ErrInvalidDifficulty Error = "invalid_difficulty"
+
+	ErrGridCompleted     Error = "grid_completed"
+	ErrMultipleSolutions Error = "multiple_solutions"
+	ErrGenerationFailed  Error = "generation_failed"
 )
 
 type Error string
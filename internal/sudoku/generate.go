@@ -0,0 +1,83 @@
+package sudoku
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+)
+
+// generateAttempts is the number of puzzles generated before giving up
+// on reaching the requested difficulty.
+const generateAttempts = 20
+
+// Generate returns a puzzle of the given difficulty with a unique
+// solution using the standard rules, along with its solution. The
+// puzzle is determined by the source of randomness.
+func Generate(ctx context.Context, difficulty Difficulty, rng *rand.Rand) (Grid, Grid, error) {
+	if difficulty.rank() < 0 {
+		return Grid{}, Grid{}, ErrInvalidDifficulty
+	}
+
+	for attempt := 0; attempt < generateAttempts; attempt++ {
+		solution, err := randomSolution(ctx, rng)
+		if err != nil {
+			return Grid{}, Grid{}, err
+		}
+
+		puzzle, grade, err := removeClues(ctx, solution, difficulty, rng)
+		if err != nil {
+			return Grid{}, Grid{}, err
+		}
+
+		if grade.Difficulty == difficulty {
+			return puzzle, solution, nil
+		}
+	}
+
+	return Grid{}, Grid{}, ErrGenerationFailed
+}
+
+// randomSolution returns a random completed grid by filling the
+// squares on the diagonal, which are independent of each other, and
+// solving the rest.
+func randomSolution(ctx context.Context, rng *rand.Rand) (Grid, error) {
+	grid := Grid{}
+	for _, outer := range []int{0, 4, 8} {
+		for inner, value := range rng.Perm(Size) {
+			row, column := squareToStandard(outer, inner)
+			grid.values[row][column] = value + 1
+		}
+	}
+
+	result, err := StandardSolve(ctx, grid, WithMaxSolutions(1))
+	if err != nil {
+		return Grid{}, err
+	}
+
+	return result.Solutions[0], nil
+}
+
+// removeClues removes the clues of the solution in a random order,
+// keeping each removal which leaves a unique solution no harder than
+// the difficulty. It returns the puzzle and its grade.
+func removeClues(ctx context.Context, solution Grid, difficulty Difficulty, rng *rand.Rand) (Grid, Grade, error) {
+	puzzle := solution.clone()
+	grade := Grade{Difficulty: Easy}
+	for _, i := range rng.Perm(Size * Size) {
+		row, column := i/Size, i%Size
+		value := puzzle.values[row][column]
+		puzzle.values[row][column] = 0
+
+		candidate, err := StandardGrade(ctx, puzzle)
+		switch {
+		case errors.Is(err, ErrMultipleSolutions) || err == nil && candidate.Difficulty.rank() > difficulty.rank():
+			puzzle.values[row][column] = value
+		case err != nil:
+			return Grid{}, Grade{}, err
+		default:
+			grade = candidate
+		}
+	}
+
+	return puzzle, grade, nil
+}
@@ -0,0 +1,60 @@
+package sudoku
+
+import (
+	"context"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestGenerate(t *testing.T) {
+	for _, difficulty := range []Difficulty{Easy, Medium, Hard} {
+		t.Run(string(difficulty), func(t *testing.T) {
+			puzzle, solution, err := Generate(context.Background(), difficulty, rand.New(rand.NewSource(1)))
+			if err != nil {
+				t.Fatalf("failed to generate puzzle: %v", err)
+			}
+
+			grade, err := StandardGrade(context.Background(), puzzle)
+			if err != nil || grade.Difficulty != difficulty {
+				t.Fatalf("expected difficulty %q, got %q (%v)", difficulty, grade.Difficulty, err)
+			}
+
+			result, err := StandardSolve(context.Background(), puzzle)
+			if err != nil || len(result.Solutions) != 1 || result.Solutions[0] != solution {
+				t.Fatalf("expected the puzzle to have the unique solution, got %d solutions (%v)", len(result.Solutions), err)
+			}
+		})
+	}
+}
+
+func TestSolver_Hint(t *testing.T) {
+	grid := DifficultExampleGrid()
+	result, _ := StandardSolve(context.Background(), grid)
+	solver := NewSolver(StandardRules()...)
+
+	for i := 0; i < 5; i++ {
+		hint, err := solver.Hint(context.Background(), grid)
+		if err != nil {
+			t.Fatalf("failed to get hint: %v", err)
+		}
+
+		expected, _ := result.Solutions[0].Get(hint.Row, hint.Column)
+		if hint.Value != expected {
+			t.Fatalf("hint %+v does not match the solution value %d", hint, expected)
+		}
+
+		_ = grid.Set(hint.Row, hint.Column, hint.Value)
+	}
+}
+
+func TestSolver_Conflicts(t *testing.T) {
+	grid := DifficultExampleGrid()
+	_ = grid.Set(0, 8, 8)
+
+	conflicts := NewSolver(StandardRules()...).Conflicts(grid)
+	expected := []Cell{{Row: 0, Column: 0}, {Row: 0, Column: 8}, {Row: 6, Column: 8}}
+	if !reflect.DeepEqual(conflicts, expected) {
+		t.Fatalf("expected conflicts %v, got %v", expected, conflicts)
+	}
+}
@@ -0,0 +1,98 @@
+package sudoku
+
+import (
+	"context"
+)
+
+// hardGuessLimit is the maximum number of guesses needed to prove the
+// solution of a hard grid is unique. Grids needing more are expert.
+const hardGuessLimit = 10
+
+// Difficulty is the difficulty of a grid for a human solver.
+type Difficulty string
+
+const (
+	// Easy grids can be solved using singles alone.
+	Easy Difficulty = "easy"
+
+	// Medium grids can be solved using every technique without
+	// guessing.
+	Medium Difficulty = "medium"
+
+	// Hard grids require a few guesses.
+	Hard Difficulty = "hard"
+
+	// Expert grids require many guesses.
+	Expert Difficulty = "expert"
+)
+
+// Difficulties returns the difficulties from easiest to hardest.
+func Difficulties() []Difficulty {
+	return []Difficulty{Easy, Medium, Hard, Expert}
+}
+
+// ParseDifficulty returns the difficulty with the given name.
+func ParseDifficulty(name string) (Difficulty, error) {
+	for _, difficulty := range Difficulties() {
+		if string(difficulty) == name {
+			return difficulty, nil
+		}
+	}
+
+	return "", ErrInvalidDifficulty
+}
+
+// rank returns the position of the difficulty from easiest to hardest.
+func (d Difficulty) rank() int {
+	for i, difficulty := range Difficulties() {
+		if difficulty == d {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// Grade is the difficulty of a grid along with the statistics of the
+// solve used to determine it.
+type Grade struct {
+	Difficulty Difficulty
+	Stats      Stats
+}
+
+// StandardGrade grades a grid using the standard rules. The grid must
+// have a unique solution, otherwise ErrMultipleSolutions is returned.
+func StandardGrade(ctx context.Context, grid Grid) (Grade, error) {
+	steps := []struct {
+		difficulty Difficulty
+		options    []Option
+	}{
+		{Easy, []Option{WithTechniques(NakedSingle, HiddenSingle), WithoutGuessing()}},
+		{Medium, []Option{WithoutGuessing()}},
+	}
+
+	for _, step := range steps {
+		result, err := StandardSolve(ctx, grid, step.options...)
+		switch err {
+		case nil:
+			return Grade{Difficulty: step.difficulty, Stats: result.Stats}, nil
+		case ErrGuessRequired:
+			continue
+		default:
+			return Grade{}, err
+		}
+	}
+
+	// Searches for a second solution to prove the solution is unique
+	result, err := StandardSolve(ctx, grid, WithMaxSolutions(2))
+	switch {
+	case err != nil:
+		return Grade{}, err
+	case len(result.Solutions) > 1:
+		return Grade{}, ErrMultipleSolutions
+	case result.Stats.Guesses <= hardGuessLimit:
+		return Grade{Difficulty: Hard, Stats: result.Stats}, nil
+	default:
+		return Grade{Difficulty: Expert, Stats: result.Stats}, nil
+	}
+}
@@ -18,7 +18,7 @@ func (grid *Grid) Clear(row, column int) error {
 	switch {
 	case row < 0 || row >= Size:
 		return ErrInvalidRow
-	case column < 0 || column > Size:
+	case column < 0 || column >= Size:
 		return ErrInvalidColumn
 	}
 
@@ -0,0 +1,72 @@
+package sudoku
+
+import (
+	"context"
+)
+
+// Hint is an entry which can be placed in a grid.
+type Hint struct {
+	Cell
+	Value int
+
+	// Technique is the technique which deduced the entry. It is empty
+	// if the entry could not be deduced and was instead taken from
+	// the first solution of the grid.
+	Technique Technique
+}
+
+// Hint returns the first entry which can be deduced using the
+// techniques of the solver. When the techniques do not place an entry,
+// the hint is the value of the cell with the fewest possibilities in
+// the first solution of the grid.
+func (s *Solver) Hint(ctx context.Context, grid Grid) (Hint, error) {
+	switch {
+	case s.isInvalid(&grid):
+		return Hint{}, ErrInvalidGrid
+	case grid.isCompleted():
+		return Hint{}, ErrGridCompleted
+	}
+
+	clone := grid.clone()
+	possibilities := s.possibilities(&clone)
+	for progress := true; progress; {
+		progress = false
+		for _, technique := range s.techniques {
+			for _, rule := range s.rules {
+				entry, used := rule.deduction(&clone, possibilities, technique)
+				if entry != nil {
+					return Hint{
+						Cell:      Cell{Row: entry.row, Column: entry.column},
+						Value:     entry.value,
+						Technique: technique,
+					}, nil
+				}
+				progress = progress || used
+			}
+
+			if progress {
+				break
+			}
+		}
+	}
+
+	var solution Grid
+	_, err := s.Stream(ctx, grid, func(s Grid) bool {
+		solution = s
+		return false
+	})
+	if err != nil {
+		return Hint{}, err
+	}
+
+	branches := MinimumRemainingValues().Branches(clone, Candidates{possibilities: possibilities}, nil)
+	if len(branches) == 0 {
+		return Hint{}, ErrNoSolution
+	}
+
+	cell := branches[0].Cell
+	return Hint{
+		Cell:  cell,
+		Value: solution.values[cell.Row][cell.Column],
+	}, nil
+}
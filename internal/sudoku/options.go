@@ -0,0 +1,102 @@
+package sudoku
+
+// Option configures a Solver.
+type Option func(solver *Solver)
+
+// WithTechniques sets the techniques the solver applies during
+// deduction. The techniques are tried in the order given and any
+// technique not given is disabled.
+func WithTechniques(techniques ...Technique) Option {
+	return func(solver *Solver) {
+		solver.techniques = append([]Technique(nil), techniques...)
+	}
+}
+
+// WithoutTechniques disables the given techniques, leaving the order
+// of the remaining techniques unchanged.
+func WithoutTechniques(techniques ...Technique) Option {
+	return func(solver *Solver) {
+		enabled := make([]Technique, 0, len(solver.techniques))
+		for _, technique := range solver.techniques {
+			if !containsTechnique(techniques, technique) {
+				enabled = append(enabled, technique)
+			}
+		}
+		solver.techniques = enabled
+	}
+}
+
+// WithoutGuessing restricts the solver to logic only. The solver
+// will not branch on a cell once the deductions are exhausted, so
+// puzzles which cannot be solved by the enabled techniques alone
+// have no solutions.
+func WithoutGuessing() Option {
+	return func(solver *Solver) {
+		solver.recursion = false
+	}
+}
+
+// WithBrancher sets the heuristic used to choose where the solver
+// guesses. The default is MinimumRemainingValues.
+func WithBrancher(brancher Brancher) Option {
+	return func(solver *Solver) {
+		solver.brancher = brancher
+	}
+}
+
+// WithMaxSolutions stops the solver once n solutions have been found.
+// A value of zero means there is no limit.
+func WithMaxSolutions(n int) Option {
+	return func(solver *Solver) {
+		solver.maxSolutions = n
+	}
+}
+
+// WithMaxNodes stops the solver once n nodes of the search tree have
+// been explored. A value of zero means there is no limit. Unlike a
+// timeout, the limit is deterministic for sequential searches.
+func WithMaxNodes(n int) Option {
+	return func(solver *Solver) {
+		solver.maxNodes = n
+	}
+}
+
+// WithMaxDepth prevents the solver from making more than n nested
+// guesses. Branches which would exceed the depth are not explored.
+// A value of zero means there is no limit.
+func WithMaxDepth(n int) Option {
+	return func(solver *Solver) {
+		solver.maxDepth = n
+	}
+}
+
+// WithParallelism explores the branches of the first depth levels of
+// the search tree concurrently using at most workers goroutines in
+// addition to the calling goroutine. The solutions are returned in
+// the same order as they would be found without parallelism.
+func WithParallelism(workers, depth int) Option {
+	return func(solver *Solver) {
+		solver.workers = workers
+		solver.parallelDepth = depth
+	}
+}
+
+// WithProgress calls report with the number of nodes of the search tree
+// explored so far once every progressNodes nodes, e.g. to show the
+// progress of a long search. The calls may be concurrent if the search
+// is parallel.
+func WithProgress(report func(nodes int)) Option {
+	return func(solver *Solver) {
+		solver.progress = report
+	}
+}
+
+func containsTechnique(techniques []Technique, technique Technique) bool {
+	for _, t := range techniques {
+		if t == technique {
+			return true
+		}
+	}
+
+	return false
+}
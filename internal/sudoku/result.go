@@ -0,0 +1,80 @@
+package sudoku
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// Limit identifies the limit which stopped a search before every
+// solution was found.
+type Limit string
+
+const (
+	// NoLimit means the search explored the whole search tree.
+	NoLimit Limit = ""
+
+	LimitTimeout   Limit = "timeout"
+	LimitCancelled Limit = "cancelled"
+	LimitNodes     Limit = "max_nodes"
+	LimitDepth     Limit = "max_depth"
+	LimitSolutions Limit = "max_solutions"
+)
+
+// Result is the result of solving a grid.
+type Result struct {
+	Solutions []Grid
+	Summary
+}
+
+// Summary describes how a search ended.
+type Summary struct {
+	// Limit is the limit which stopped the search, if any.
+	Limit Limit
+
+	// Stats are the statistics of the search.
+	Stats Stats
+}
+
+// Completed returns true if the search found every solution.
+func (s Summary) Completed() bool {
+	return s.Limit == NoLimit
+}
+
+// Stats are the statistics collected during a search.
+type Stats struct {
+	// Nodes is the number of nodes of the search tree explored.
+	Nodes int
+
+	// MaxDepth is the largest number of guesses made along a
+	// single branch of the search tree.
+	MaxDepth int
+
+	// Guesses is the number of values tried while branching.
+	Guesses int
+
+	// Deductions is the number of times each technique made
+	// progress.
+	Deductions map[Technique]int
+
+	// Duration is the time taken by the search.
+	Duration time.Duration
+
+	// DeductionTime is the time spent applying the techniques. When
+	// searching concurrently it is summed over every goroutine, so
+	// it may exceed the duration.
+	DeductionTime time.Duration
+}
+
+// contextLimit returns the limit corresponding to the error of a
+// done context.
+func contextLimit(ctx context.Context) Limit {
+	switch {
+	case ctx.Err() == nil:
+		return NoLimit
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		return LimitTimeout
+	default:
+		return LimitCancelled
+	}
+}
@@ -10,15 +10,22 @@ type Rule interface {
 	isInvalid(grid *Grid) bool
 
 	// deduction must return the first valid entry it finds
-	// and true if either an entry was found or the possibilities
-	// where changed.
-	deduction(grid *Grid, possibilities *possibilities) (*entry, bool)
+	// using the given technique and true if either an entry
+	// was found or the possibilities where changed. Rules which
+	// do not support the technique must return nil and false.
+	deduction(grid *Grid, possibilities *possibilities, technique Technique) (*entry, bool)
 
 	// restrict must restrict the possibilities (in line
 	// with this rule) based on the entry given.
 	restrict(entry *entry, possibilities *possibilities)
 }
 
+// unitRule is implemented by rules which require every value to
+// appear exactly once in each of a set of units, e.g. each row.
+type unitRule interface {
+	unit() indicesConverter
+}
+
 type entry struct {
 	row    int
 	column int
@@ -37,7 +44,11 @@ func (trivialRule) isInvalid(_ *Grid) bool {
 	return false
 }
 
-func (trivialRule) deduction(grid *Grid, possibilities *possibilities) (*entry, bool) {
+func (trivialRule) deduction(grid *Grid, possibilities *possibilities, technique Technique) (*entry, bool) {
+	if technique != NakedSingle {
+		return nil, false
+	}
+
 	for row := 0; row < Size; row++ {
 		for column := 0; column < Size; column++ {
 			if grid.values[row][column] == 0 && len(possibilities[row][column]) == 1 {
@@ -93,20 +104,12 @@ func (rowRule) isInvalid(grid *Grid) bool {
 	return false
 }
 
-func (rowRule) deduction(grid *Grid, possibilities *possibilities) (*entry, bool) {
-	entry := singlePositionLogic(grid, possibilities, fixedRowConverter)
-	if entry != nil {
-		return entry, true
-	}
-
-	applied := false
-	for n := 2; n < 9; n++ {
-		if limitPossibilitiesLogic(grid, possibilities, fixedRowConverter, n) {
-			applied = true
-		}
-	}
+func (rowRule) deduction(grid *Grid, possibilities *possibilities, technique Technique) (*entry, bool) {
+	return unitDeduction(grid, possibilities, fixedRowConverter, technique)
+}
 
-	return nil, applied
+func (rowRule) unit() indicesConverter {
+	return fixedRowConverter
 }
 
 func (rowRule) restrict(entry *entry, possibilities *possibilities) {
@@ -152,20 +155,12 @@ func (columnRule) isInvalid(grid *Grid) bool {
 	return false
 }
 
-func (columnRule) deduction(grid *Grid, possibilities *possibilities) (*entry, bool) {
-	entry := singlePositionLogic(grid, possibilities, fixedColumnConverter)
-	if entry != nil {
-		return entry, true
-	}
-
-	applied := false
-	for n := 2; n < 9; n++ {
-		if limitPossibilitiesLogic(grid, possibilities, fixedColumnConverter, n) {
-			applied = true
-		}
-	}
+func (columnRule) deduction(grid *Grid, possibilities *possibilities, technique Technique) (*entry, bool) {
+	return unitDeduction(grid, possibilities, fixedColumnConverter, technique)
+}
 
-	return nil, applied
+func (columnRule) unit() indicesConverter {
+	return fixedColumnConverter
 }
 
 func (columnRule) restrict(entry *entry, possibilities *possibilities) {
@@ -211,20 +206,12 @@ func (squareRule) isInvalid(grid *Grid) bool {
 	return false
 }
 
-func (squareRule) deduction(grid *Grid, possibilities *possibilities) (*entry, bool) {
-	entry := singlePositionLogic(grid, possibilities, squareToStandard)
-	if entry != nil {
-		return entry, true
-	}
-
-	applied := false
-	for n := 2; n < 9; n++ {
-		if limitPossibilitiesLogic(grid, possibilities, squareToStandard, n) {
-			applied = true
-		}
-	}
+func (squareRule) deduction(grid *Grid, possibilities *possibilities, technique Technique) (*entry, bool) {
+	return unitDeduction(grid, possibilities, squareToStandard, technique)
+}
 
-	return nil, applied
+func (squareRule) unit() indicesConverter {
+	return squareToStandard
 }
 
 func (squareRule) restrict(entry *entry, possibilities *possibilities) {
@@ -250,6 +237,26 @@ func isValidPair(row, column int) bool {
 	return false
 }
 
+// unitDeduction applies the technique to every unit described by
+// the converter, e.g. every row for fixedRowConverter.
+func unitDeduction(grid *Grid, possibilities *possibilities, convert indicesConverter, technique Technique) (*entry, bool) {
+	switch technique {
+	case HiddenSingle:
+		entry := singlePositionLogic(grid, possibilities, convert)
+		return entry, entry != nil
+	case NakedSubset:
+		applied := false
+		for n := 2; n < 9; n++ {
+			if limitPossibilitiesLogic(grid, possibilities, convert, n) {
+				applied = true
+			}
+		}
+		return nil, applied
+	}
+
+	return nil, false
+}
+
 func singlePositionLogic(grid *Grid, possibilities *possibilities, convert indicesConverter) *entry {
 	// Loops through fixed index and value
 	for fixed := 0; fixed < Size; fixed++ {
@@ -0,0 +1,357 @@
+package sudoku
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel"
+)
+
+// tracer records the phases of each search. Spans are discarded unless
+// a tracer provider is installed.
+var tracer = otel.Tracer("github.com/PeterEFinch/sudoku-solver/internal/sudoku")
+
+// progressNodes is the number of nodes explored between the reports of
+// the progress of a search.
+const progressNodes = 1000
+
+// emitter receives the solutions found by a search and returns false
+// if the search should stop.
+type emitter func(solution Grid) bool
+
+// search holds the state of a single call to a solver.
+type search struct {
+	*Solver
+
+	// ctx is cancelled once a limit of the solver is reached.
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	// workers limits the number of goroutines exploring branches
+	// concurrently. It is nil when the search is sequential.
+	workers chan struct{}
+
+	// The counters are updated atomically as branches may be
+	// explored concurrently.
+	nodes         int64
+	deepest       int64
+	guesses       int64
+	deductionTime int64
+	depthLimited  int32
+	guessRequired int32
+
+	// units are the units of the rules given to the brancher.
+	units []Unit
+
+	start time.Time
+
+	mu         sync.Mutex
+	limit      Limit
+	deductions map[Technique]int
+}
+
+func newSearch(ctx context.Context, solver *Solver) *search {
+	s := &search{
+		Solver:     solver,
+		units:      solver.units(),
+		start:      time.Now(),
+		deductions: make(map[Technique]int),
+	}
+	s.ctx, s.cancel = context.WithCancel(ctx)
+	if solver.workers > 0 && solver.parallelDepth > 0 {
+		s.workers = make(chan struct{}, solver.workers)
+	}
+
+	return s
+}
+
+// stop records the limit which stopped the search and cancels the
+// remaining branches. Only the first limit is recorded.
+func (s *search) stop(limit Limit) {
+	s.mu.Lock()
+	if s.limit == NoLimit {
+		s.limit = limit
+	}
+	s.mu.Unlock()
+
+	s.cancel()
+}
+
+// summary returns the summary of the search, where ctx is the context
+// the search was started with.
+func (s *search) summary(ctx context.Context) Summary {
+	s.mu.Lock()
+	limit := s.limit
+	deductions := make(map[Technique]int, len(s.deductions))
+	for technique, count := range s.deductions {
+		deductions[technique] = count
+	}
+	s.mu.Unlock()
+
+	if limit == NoLimit {
+		limit = contextLimit(ctx)
+	}
+
+	if limit == NoLimit && atomic.LoadInt32(&s.depthLimited) == 1 {
+		limit = LimitDepth
+	}
+
+	return Summary{
+		Limit: limit,
+		Stats: Stats{
+			Nodes:         int(atomic.LoadInt64(&s.nodes)),
+			MaxDepth:      int(atomic.LoadInt64(&s.deepest)),
+			Guesses:       int(atomic.LoadInt64(&s.guesses)),
+			Deductions:    deductions,
+			Duration:      time.Since(s.start),
+			DeductionTime: time.Duration(atomic.LoadInt64(&s.deductionTime)),
+		},
+	}
+}
+
+// err returns the error describing the outcome of the search given
+// its summary and the number of solutions found.
+func (s *search) err(summary Summary, count int) error {
+	switch {
+	case summary.Limit == LimitTimeout:
+		return ErrTimeout
+	case summary.Limit == LimitCancelled:
+		return ErrCancelled
+	case count > 0 || summary.Limit != NoLimit:
+		return nil
+	case atomic.LoadInt32(&s.guessRequired) == 1:
+		return ErrGuessRequired
+	default:
+		return ErrNoSolution
+	}
+}
+
+// visit records that a node at the given depth is being explored and
+// returns false if the node limit has been reached.
+func (s *search) visit(depth int) bool {
+	if s.maxNodes > 0 && atomic.LoadInt64(&s.nodes) >= int64(s.maxNodes) {
+		s.stop(LimitNodes)
+		return false
+	}
+
+	nodes := atomic.AddInt64(&s.nodes, 1)
+	if s.progress != nil && nodes%progressNodes == 0 {
+		s.progress(int(nodes))
+	}
+
+	for {
+		current := atomic.LoadInt64(&s.deepest)
+		if int64(depth) <= current || atomic.CompareAndSwapInt64(&s.deepest, current, int64(depth)) {
+			return true
+		}
+	}
+}
+
+// deduction applies the deductions of the solver to the grid while
+// recording their statistics.
+func (s *search) deduction(ctx context.Context, grid *Grid) (*possibilities, bool) {
+	start := time.Now()
+	deductions := make(map[Technique]int)
+	possibilities, ok := s.Solver.deduction(ctx, grid, deductions)
+	atomic.AddInt64(&s.deductionTime, int64(time.Since(start)))
+
+	s.mu.Lock()
+	for technique, count := range deductions {
+		s.deductions[technique] += count
+	}
+	s.mu.Unlock()
+
+	return possibilities, ok
+}
+
+// trace starts a span for a phase of the search if the node is the
+// root of the search tree, returning the function which ends it. Deeper
+// nodes are not traced as there may be millions of them.
+func (s *search) trace(ctx context.Context, depth int, name string) (context.Context, func()) {
+	if depth > 0 {
+		return ctx, func() {}
+	}
+
+	ctx, span := tracer.Start(ctx, name)
+	return ctx, func() { span.End() }
+}
+
+// solve emits every solution of the grid and returns false if the
+// emitter stopped the search.
+func (s *search) solve(ctx context.Context, grid *Grid, depth int, emit emitter) bool {
+	// Checks if it is invalid or if is filled
+	switch {
+	case ctx.Err() != nil:
+		return true
+	case !s.visit(depth):
+		return true
+	case s.isInvalid(grid):
+		return true
+	case grid.isCompleted():
+		return emit(*grid)
+	}
+
+	// Apply deduction logic
+	_, end := s.trace(ctx, depth, "sudoku.deduction")
+	possibilities, ok := s.deduction(ctx, grid)
+	end()
+	if !ok {
+		return true
+	}
+
+	// Checks if it is now invalid or if is filled
+	switch {
+	case ctx.Err() != nil:
+		return true
+	case s.isInvalid(grid):
+		return true
+	case grid.isCompleted():
+		return emit(*grid)
+	}
+
+	// Stops if the solver is restricted to logic only
+	if !s.recursion {
+		atomic.StoreInt32(&s.guessRequired, 1)
+		return true
+	}
+
+	// Stops if guessing would exceed the maximum depth
+	if s.maxDepth > 0 && depth >= s.maxDepth {
+		atomic.StoreInt32(&s.depthLimited, 1)
+		return true
+	}
+
+	ctx, end = s.trace(ctx, depth, "sudoku.search")
+	defer end()
+
+	branches := s.branches(grid, possibilities)
+	if s.workers != nil && depth < s.parallelDepth {
+		return s.branchConcurrently(ctx, grid, depth, branches, emit)
+	}
+
+	// A recursive method for generating solutions
+	for _, branch := range branches {
+		if ctx.Err() != nil {
+			break
+		}
+
+		atomic.AddInt64(&s.guesses, 1)
+		clone := grid.clone()
+		clone.values[branch.Row][branch.Column] = branch.Value
+		if !s.isInvalid(&clone) && !s.solve(ctx, &clone, depth+1, emit) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// branches returns the entries of the brancher to try in turn,
+// ignoring any entry which is not a value of an empty cell so that
+// branchers outside this package cannot corrupt the search.
+func (s *search) branches(grid *Grid, possibilities *possibilities) []Entry {
+	branches := s.brancher.Branches(*grid, Candidates{possibilities: possibilities}, s.units)
+	valid := branches[:0:0]
+	for _, branch := range branches {
+		row, column := branch.Row, branch.Column
+		if isValidPair(row, column) && branch.Value >= 1 && branch.Value <= Size && grid.isCellEmpty(row, column) {
+			valid = append(valid, branch)
+		}
+	}
+
+	return valid
+}
+
+// branchResult collects the solutions of a single branch explored
+// concurrently.
+type branchResult struct {
+	solutions []Grid
+	done      chan struct{}
+}
+
+// branchConcurrently explores the branches on the available workers,
+// falling back to the current goroutine when all workers are busy.
+// The solutions are emitted in the order of the branches so that the
+// output matches the sequential search.
+func (s *search) branchConcurrently(ctx context.Context, grid *Grid, depth int, branches []Entry, emit emitter) bool {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make([]*branchResult, len(branches))
+	emitted := 0
+
+	// flush emits the solutions of the completed branches in order,
+	// waiting for the branches to complete if wait is true. It returns
+	// false once the emitter stops the search, cancelling the remaining
+	// branches.
+	flush := func(wait bool) bool {
+		for ; emitted < len(results) && results[emitted] != nil; emitted++ {
+			result := results[emitted]
+			if wait {
+				<-result.done
+			}
+
+			select {
+			case <-result.done:
+			default:
+				return true
+			}
+
+			for _, solution := range result.solutions {
+				if !emit(solution) {
+					cancel()
+					for _, r := range results {
+						if r != nil {
+							<-r.done
+						}
+					}
+					return false
+				}
+			}
+		}
+
+		return true
+	}
+
+	for i, branch := range branches {
+		if ctx.Err() != nil {
+			break
+		}
+
+		if !flush(false) {
+			return false
+		}
+
+		atomic.AddInt64(&s.guesses, 1)
+		result := &branchResult{done: make(chan struct{})}
+		results[i] = result
+
+		clone := grid.clone()
+		clone.values[branch.Row][branch.Column] = branch.Value
+		explore := func() {
+			defer close(result.done)
+			if s.isInvalid(&clone) {
+				return
+			}
+
+			s.solve(ctx, &clone, depth+1, func(solution Grid) bool {
+				result.solutions = append(result.solutions, solution)
+				return !s.isSolutionLimitReached(len(result.solutions))
+			})
+		}
+
+		select {
+		case s.workers <- struct{}{}:
+			go func() {
+				defer func() { <-s.workers }()
+				explore()
+			}()
+		default:
+			explore()
+		}
+	}
+
+	return flush(true)
+}
@@ -4,37 +4,124 @@ import (
 	"context"
 )
 
-// Sta
-func StandardSolve(ctx context.Context, grid Grid) []Grid {
-	solver := NewSolver(
+// StandardSolve solves the grid using the standard rules. See
+// Solver.Solve.
+func StandardSolve(ctx context.Context, grid Grid, options ...Option) (Result, error) {
+	solver := NewSolverWithOptions(StandardRules(), options...)
+
+	return solver.Solve(ctx, grid)
+}
+
+// StandardStream streams the solutions of the grid using the standard
+// rules. See Solver.Stream.
+func StandardStream(ctx context.Context, grid Grid, yield func(solution Grid) bool, options ...Option) (Summary, error) {
+	solver := NewSolverWithOptions(StandardRules(), options...)
+
+	return solver.Stream(ctx, grid, yield)
+}
+
+// StandardRules returns the rules of a standard sudoku.
+func StandardRules() []Rule {
+	return []Rule{
+		TrivialRule(),
 		RowRule(),
 		ColumnRule(),
 		SquareRule(),
-	)
-
-	return solver.Solve(ctx, grid)
+	}
 }
 
 type Solver struct {
-	recursion bool
-	rules     []Rule
+	recursion     bool
+	rules         []Rule
+	techniques    []Technique
+	brancher      Brancher
+	maxSolutions  int
+	maxNodes      int
+	maxDepth      int
+	workers       int
+	parallelDepth int
+	progress      func(nodes int)
 }
 
+// NewSolver returns a solver of the rules using the default
+// techniques and brancher, and no limits.
 func NewSolver(rules ...Rule) *Solver {
-	return &Solver{
-		recursion: true,
-		rules:     rules,
+	return NewSolverWithOptions(rules)
+}
+
+// NewSolverWithOptions returns a solver of the rules configured by the
+// options, which are applied in order.
+func NewSolverWithOptions(rules []Rule, options ...Option) *Solver {
+	solver := &Solver{
+		recursion:  true,
+		rules:      rules,
+		techniques: DefaultTechniques(),
+		brancher:   MinimumRemainingValues(),
+	}
+
+	for _, option := range options {
+		option(solver)
+	}
+
+	return solver
+}
+
+// Solve returns the solutions of the grid along with a summary of
+// the search. See Stream for the errors returned, in which case the
+// result contains the solutions found before the error occurred.
+func (s *Solver) Solve(ctx context.Context, grid Grid) (Result, error) {
+	solutions := make([]Grid, 0)
+	summary, err := s.Stream(ctx, grid, func(solution Grid) bool {
+		solutions = append(solutions, solution)
+		return true
+	})
+
+	result := Result{
+		Solutions: solutions,
+		Summary:   summary,
 	}
+
+	return result, err
 }
 
-func (s *Solver) Solve(ctx context.Context, grid Grid) []Grid {
+// Stream calls yield with each solution of the grid as soon as it is
+// found. The search stops when yield returns false, the context is
+// done or one of the limits of the solver is reached. When searching
+// concurrently, solutions are yielded once every branch before them
+// has been explored so that the order is unchanged.
+//
+// The error is ErrInvalidGrid if the grid violates the rules,
+// ErrTimeout or ErrCancelled if the context is done before the search
+// completes, ErrGuessRequired if the grid cannot be solved without
+// guessing and ErrNoSolution if the search completes without finding
+// a solution. Reaching one of the limits of the solver is not an
+// error and is reported by the summary instead.
+func (s *Solver) Stream(ctx context.Context, grid Grid, yield func(solution Grid) bool) (Summary, error) {
 	if s.isInvalid(&grid) {
-		return nil
+		return Summary{}, ErrInvalidGrid
 	}
 
+	search := newSearch(ctx, s)
+	defer search.cancel()
+
+	count := 0
 	clone := grid.clone()
+	search.solve(search.ctx, &clone, 0, func(solution Grid) bool {
+		count++
+		if !yield(solution) {
+			return false
+		}
+
+		if s.isSolutionLimitReached(count) {
+			search.stop(LimitSolutions)
+			return false
+		}
+
+		return true
+	})
 
-	return s.solve(ctx, &clone)
+	summary := search.summary(ctx)
+	return summary, search.err(summary, count)
 }
 
 func (s *Solver) isInvalid(grid *Grid) bool {
@@ -47,12 +134,32 @@ func (s *Solver) isInvalid(grid *Grid) bool {
 	return false
 }
 
-func (s *Solver) deduction(ctx context.Context, grid *Grid) bool {
-	if ctx.Err() != nil {
-		return true
+// units returns the units of the rules which define them.
+func (s *Solver) units() []Unit {
+	var units []Unit
+	for _, rule := range s.rules {
+		r, ok := rule.(unitRule)
+		if !ok {
+			continue
+		}
+
+		convert := r.unit()
+		for fixed := 0; fixed < Size; fixed++ {
+			var unit Unit
+			for variable := 0; variable < Size; variable++ {
+				row, column := convert(fixed, variable)
+				unit[variable] = Cell{Row: row, Column: column}
+			}
+			units = append(units, unit)
+		}
 	}
 
-	// Initialises possibilities and applies the restrictions
+	return units
+}
+
+// possibilities initialises the possibilities of the grid and applies
+// the restrictions of the rules for every filled cell.
+func (s *Solver) possibilities(grid *Grid) *possibilities {
 	possibilities := &possibilities{}
 	possibilities.initialise()
 	for row := 0; row < Size; row++ {
@@ -72,100 +179,72 @@ func (s *Solver) deduction(ctx context.Context, grid *Grid) bool {
 		}
 	}
 
+	return possibilities
+}
+
+// deduction applies the techniques to the grid until no further
+// progress is made, counting the deductions made by each technique.
+// It returns the remaining possibilities and false if the grid was
+// found to be invalid.
+func (s *Solver) deduction(ctx context.Context, grid *Grid, deductions map[Technique]int) (*possibilities, bool) {
+	if ctx.Err() != nil {
+		return nil, true
+	}
+
+	possibilities := s.possibilities(grid)
+
 	// Repeatedly applies the deductions of the rules
 	active := true
 	for active && ctx.Err() == nil {
 		active = false
 
-		// Applies the deductions of every rule
-		for _, rule := range s.rules {
-			if ctx.Err() != nil {
-				break
-			}
+		// Applies the techniques in order, returning to the first
+		// technique as soon as any of them makes progress
+		for _, technique := range s.techniques {
+			// Applies the deductions of every rule
+			for _, rule := range s.rules {
+				if ctx.Err() != nil {
+					break
+				}
 
-			// Checks if any deduction can be made
-			entry, used := rule.deduction(grid, possibilities)
-			if !used {
-				continue
-			}
+				// Checks if any deduction can be made
+				entry, used := rule.deduction(grid, possibilities, technique)
+				if !used {
+					continue
+				}
 
-			// Record that the process is still active and
-			// checks if there is something to be set
-			active = true
-			if entry == nil {
-				continue
-			}
+				// Record that the process is still active and
+				// checks if there is something to be set
+				active = true
+				deductions[technique]++
+				if entry == nil {
+					continue
+				}
 
-			// Updates grid and possibilities using deduction
-			grid.values[entry.row][entry.column] = entry.value
-			possibilities.set(entry.row, entry.column, entry.value)
-			for _, r := range s.rules {
-				r.restrict(entry, possibilities)
-			}
+				// Updates grid and possibilities using deduction
+				grid.values[entry.row][entry.column] = entry.value
+				possibilities.set(entry.row, entry.column, entry.value)
+				for _, r := range s.rules {
+					r.restrict(entry, possibilities)
+				}
 
-			// Checks if grid or  possibilities have become invalid
-			if s.isInvalid(grid) && possibilities.isInvalid() {
-				return false
+				// Checks if grid or  possibilities have become invalid
+				if s.isInvalid(grid) && possibilities.isInvalid() {
+					return nil, false
+				}
 			}
-		}
-	}
 
-	return true
-}
-
-func (s *Solver) solve(ctx context.Context, grid *Grid) []Grid {
-	// Checks if it is invalid or if is filled
-	switch {
-	case ctx.Err() != nil:
-		return nil
-	case s.isInvalid(grid):
-		return nil
-	case grid.isCompleted():
-		return []Grid{*grid}
-	}
-
-	// Apply deduction logic
-	ok := s.deduction(ctx, grid)
-	if !ok {
-		return nil
-	}
-
-	// Checks if it is now invalid or if is filled
-	switch {
-	case ctx.Err() != nil:
-		return nil
-	case s.isInvalid(grid):
-		return nil
-	case grid.isCompleted():
-		return []Grid{*grid}
-	}
-
-	// A recursive method for generating solutions
-	for row := 0; row < Size; row++ {
-		for column := 0; column < Size; column++ {
-			if ctx.Err() != nil {
+			if active {
 				break
 			}
-
-			// Skips value if already set
-			if grid.values[row][column] > 0 {
-				continue
-			}
-
-			// Loops through possible values
-			solutions := make([]Grid, 0)
-			for value := 1; value <= Size; value++ {
-				grid.values[row][column] = value
-				if !s.isInvalid(grid) {
-					clone := grid.clone()
-					solutions = append(solutions, s.solve(ctx, &clone)...)
-				}
-				grid.values[row][column] = 0
-			}
-
-			return solutions
 		}
 	}
 
-	return nil
+	return possibilities, true
+}
+
+// isSolutionLimitReached returns true if the number of solutions has
+// reached the maximum number of solutions.
+func (s *Solver) isSolutionLimitReached(count int) bool {
+	return s.maxSolutions > 0 && count >= s.maxSolutions
 }
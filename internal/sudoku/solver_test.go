@@ -3,6 +3,8 @@ package sudoku
 import (
 	"context"
 	"fmt"
+	"testing"
+	"time"
 )
 
 func ExampleStandardSolve() {
@@ -16,8 +18,8 @@ func ExampleStandardSolve() {
 	fmt.Println("--------------------")
 	fmt.Println("Solution")
 	fmt.Println("--------------------")
-	solutions := StandardSolve(context.Background(), grid)
-	fmt.Print(solutions[0].String())
+	result, _ := StandardSolve(context.Background(), grid)
+	fmt.Print(result.Solutions[0].String())
 
 	// Output:
 	//--------------------
@@ -46,3 +48,176 @@ func ExampleStandardSolve() {
 	//4 3 8 5 2 6 9 1 7
 	//7 9 6 3 1 8 4 5 2
 }
+
+func TestSolver_WithoutGuessing(t *testing.T) {
+	grid := DifficultExampleGrid()
+
+	result, err := StandardSolve(context.Background(), grid, WithoutGuessing())
+	if err != ErrGuessRequired || len(result.Solutions) != 0 {
+		t.Fatalf("expected no solutions without guessing, got %d (%v)", len(result.Solutions), err)
+	}
+
+	result, err = StandardSolve(context.Background(), grid, WithoutTechniques(NakedSubset))
+	if err != nil || len(result.Solutions) != 1 {
+		t.Fatalf("expected 1 solution, got %d (%v)", len(result.Solutions), err)
+	}
+}
+
+func TestSolver_Branchers(t *testing.T) {
+	branchers := map[string]Brancher{
+		"first empty cell":         FirstEmptyCell(),
+		"minimum remaining values": MinimumRemainingValues(),
+		"most constrained digit":   MostConstrainedDigit(),
+		"custom":                   lastEmptyCell{},
+	}
+
+	expected, _ := StandardSolve(context.Background(), DifficultExampleGrid())
+	for name, brancher := range branchers {
+		t.Run(name, func(t *testing.T) {
+			result, err := StandardSolve(context.Background(), DifficultExampleGrid(), WithBrancher(brancher))
+			if err != nil || len(result.Solutions) != 1 || result.Solutions[0] != expected.Solutions[0] {
+				t.Fatalf("expected the unique solution, got %d solutions (%v)", len(result.Solutions), err)
+			}
+		})
+	}
+}
+
+// lastEmptyCell is a brancher using only the exported API, branching
+// on the last empty cell in row-major order.
+type lastEmptyCell struct{}
+
+func (lastEmptyCell) Branches(grid Grid, candidates Candidates, _ []Unit) []Entry {
+	for row := Size - 1; row >= 0; row-- {
+		for column := Size - 1; column >= 0; column-- {
+			if value, _ := grid.Get(row, column); value != 0 {
+				continue
+			}
+
+			cell := Cell{Row: row, Column: column}
+			entries := make([]Entry, 0, candidates.Count(cell))
+			for _, value := range candidates.Values(cell) {
+				entries = append(entries, Entry{Cell: cell, Value: value})
+			}
+			return entries
+		}
+	}
+
+	return nil
+}
+
+func BenchmarkSolver_Branchers(b *testing.B) {
+	branchers := []struct {
+		name     string
+		brancher Brancher
+	}{
+		{"FirstEmptyCell", FirstEmptyCell()},
+		{"MinimumRemainingValues", MinimumRemainingValues()},
+		{"MostConstrainedDigit", MostConstrainedDigit()},
+	}
+
+	for _, bc := range branchers {
+		b.Run(bc.name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				_, _ = StandardSolve(context.Background(), DifficultExampleGrid(), WithBrancher(bc.brancher))
+			}
+		})
+	}
+}
+
+func TestSolver_WithParallelism(t *testing.T) {
+	grid := Grid{}
+
+	result, _ := StandardSolve(context.Background(), grid, WithMaxSolutions(20))
+	expected := result.Solutions
+	if len(expected) != 20 {
+		t.Fatalf("expected 20 solutions, got %d", len(expected))
+	}
+
+	for i := 0; i < 5; i++ {
+		result, _ := StandardSolve(context.Background(), grid, WithMaxSolutions(20), WithParallelism(4, 2))
+		solutions := result.Solutions
+		if len(solutions) != len(expected) {
+			t.Fatalf("expected %d solutions, got %d", len(expected), len(solutions))
+		}
+
+		for j := range solutions {
+			if solutions[j] != expected[j] {
+				t.Fatalf("solution %d differs from the sequential search", j)
+			}
+		}
+	}
+}
+
+func TestSolver_Limits(t *testing.T) {
+	tests := []struct {
+		name   string
+		option Option
+		limit  Limit
+	}{
+		{"no limit", WithMaxNodes(0), NoLimit},
+		{"max nodes", WithMaxNodes(10), LimitNodes},
+		{"max depth", WithMaxDepth(1), LimitDepth},
+		{"max solutions", WithMaxSolutions(1), LimitSolutions},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, _ := StandardSolve(context.Background(), DifficultExampleGrid(), tt.option)
+			if result.Limit != tt.limit {
+				t.Fatalf("expected limit %q, got %q", tt.limit, result.Limit)
+			}
+
+			if tt.limit == LimitNodes && result.Stats.Nodes != 10 {
+				t.Fatalf("expected 10 nodes, got %d", result.Stats.Nodes)
+			}
+		})
+	}
+}
+
+func TestSolver_WithProgress(t *testing.T) {
+	var reports []int
+	result, _ := StandardSolve(context.Background(), Grid{}, WithMaxNodes(2500), WithProgress(func(nodes int) {
+		reports = append(reports, nodes)
+	}))
+
+	if result.Stats.Nodes != 2500 || len(reports) != 2 || reports[0] != 1000 || reports[1] != 2000 {
+		t.Fatalf("expected progress at 1000 and 2000 of 2500 nodes, got %v of %d nodes", reports, result.Stats.Nodes)
+	}
+}
+
+func TestSolver_Errors(t *testing.T) {
+	invalid := Grid{}
+	_ = invalid.Set(0, 0, 1)
+	_ = invalid.Set(0, 1, 1)
+
+	unsolvable := Grid{}
+	for column := 0; column < Size-1; column++ {
+		_ = unsolvable.Set(0, column, column+1)
+	}
+	_ = unsolvable.Set(1, 8, 9)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Nanosecond)
+	defer cancel()
+	<-ctx.Done()
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		grid Grid
+		err  error
+	}{
+		{"solved", context.Background(), DifficultExampleGrid(), nil},
+		{"invalid grid", context.Background(), invalid, ErrInvalidGrid},
+		{"no solution", context.Background(), unsolvable, ErrNoSolution},
+		{"timeout", ctx, DifficultExampleGrid(), ErrTimeout},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := StandardSolve(tt.ctx, tt.grid)
+			if err != tt.err {
+				t.Fatalf("expected error %v, got %v", tt.err, err)
+			}
+		})
+	}
+}
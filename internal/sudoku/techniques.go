@@ -0,0 +1,40 @@
+package sudoku
+
+// Technique is a deduction technique which the rules of a solver
+// may apply to restrict the possibilities or place entries.
+type Technique string
+
+const (
+	// NakedSingle places a value in a cell which has only one
+	// possibility remaining.
+	NakedSingle Technique = "naked_single"
+
+	// HiddenSingle places a value in the only cell of a unit where
+	// it is still possible.
+	HiddenSingle Technique = "hidden_single"
+
+	// NakedSubset removes possibilities from a unit when n cells of
+	// that unit share the same n possibilities.
+	NakedSubset Technique = "naked_subset"
+)
+
+// DefaultTechniques returns the techniques used by a solver when none
+// have been configured, in the order in which they are tried.
+func DefaultTechniques() []Technique {
+	return []Technique{
+		NakedSingle,
+		HiddenSingle,
+		NakedSubset,
+	}
+}
+
+// ParseTechnique returns the technique with the given name.
+func ParseTechnique(name string) (Technique, error) {
+	for _, technique := range DefaultTechniques() {
+		if string(technique) == name {
+			return technique, nil
+		}
+	}
+
+	return "", ErrInvalidTechnique
+}
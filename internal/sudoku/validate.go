@@ -0,0 +1,48 @@
+package sudoku
+
+// Cell is the position of a cell in a grid, indexed from zero.
+type Cell struct {
+	Row    int
+	Column int
+}
+
+// Conflicts returns the filled cells of the grid which break one of the
+// rules of the solver together with another filled cell, in row-major
+// order. The grid is valid if there are no conflicts.
+func (s *Solver) Conflicts(grid Grid) []Cell {
+	if !s.isInvalid(&grid) {
+		return nil
+	}
+
+	var filled []Cell
+	for row := 0; row < Size; row++ {
+		for column := 0; column < Size; column++ {
+			if grid.isCellFilled(row, column) {
+				filled = append(filled, Cell{Row: row, Column: column})
+			}
+		}
+	}
+
+	// Checks every pair of filled cells in isolation
+	conflicting := make(map[Cell]bool)
+	for i, a := range filled {
+		for _, b := range filled[i+1:] {
+			pair := Grid{}
+			pair.values[a.Row][a.Column] = grid.values[a.Row][a.Column]
+			pair.values[b.Row][b.Column] = grid.values[b.Row][b.Column]
+			if s.isInvalid(&pair) {
+				conflicting[a] = true
+				conflicting[b] = true
+			}
+		}
+	}
+
+	conflicts := make([]Cell, 0, len(conflicting))
+	for _, cell := range filled {
+		if conflicting[cell] {
+			conflicts = append(conflicts, cell)
+		}
+	}
+
+	return conflicts
+}
@@ -0,0 +1,87 @@
+// Package tracing configures OpenTelemetry tracing for the server and
+// correlates log lines with the spans they were written in.
+package tracing
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	serviceName         = "sudoku-solver"
+	instrumentationName = "github.com/PeterEFinch/sudoku-solver"
+)
+
+// Start exports spans via OTLP over gRPC to the endpoint, e.g.
+// localhost:4317. The returned function flushes the remaining spans
+// and must be called before the server exits.
+func Start(ctx context.Context, endpoint string, insecure bool) (func(context.Context) error, error) {
+	options := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(endpoint)}
+	if insecure {
+		options = append(options, otlptracegrpc.WithInsecure())
+	}
+
+	exporter, err := otlptracegrpc.New(ctx, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	provider := Install(sdktrace.NewBatchSpanProcessor(exporter))
+	return provider.Shutdown, nil
+}
+
+// Install sets the global tracer provider, which samples every span and
+// passes them to the processor, and propagates trace contexts using
+// the W3C headers. Tests can install an in-memory exporter using
+//
+//	tracing.Install(sdktrace.NewSimpleSpanProcessor(tracetest.NewInMemoryExporter()))
+func Install(processor sdktrace.SpanProcessor) *sdktrace.TracerProvider {
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithSpanProcessor(processor),
+		sdktrace.WithResource(resource.NewSchemaless(semconv.ServiceName(serviceName))),
+	)
+
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	return provider
+}
+
+// StartRequest starts a server span for the request, continuing the
+// trace of the client if the request has a trace context.
+func StartRequest(req *http.Request, name string) (context.Context, trace.Span) {
+	ctx := otel.GetTextMapPropagator().Extract(req.Context(), propagation.HeaderCarrier(req.Header))
+	return Tracer().Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(semconv.HTTPMethod(req.Method), semconv.HTTPTarget(req.URL.Path)),
+	)
+}
+
+// Tracer returns the tracer of the server. Spans are discarded until a
+// tracer provider is installed.
+func Tracer() trace.Tracer {
+	return otel.Tracer(instrumentationName)
+}
+
+// Logger returns the global logger with the ids of the span of the
+// context, if any, added to every log line.
+func Logger(ctx context.Context) *zerolog.Logger {
+	logger := log.Logger
+	if spanContext := trace.SpanContextFromContext(ctx); spanContext.IsValid() {
+		logger = logger.With().
+			Str("trace_id", spanContext.TraceID().String()).
+			Str("span_id", spanContext.SpanID().String()).
+			Logger()
+	}
+
+	return &logger
+}
@@ -0,0 +1,42 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestLogger(t *testing.T) {
+	provider := Install(sdktrace.NewSimpleSpanProcessor(tracetest.NewInMemoryExporter()))
+	defer provider.Shutdown(context.Background())
+
+	buf := &bytes.Buffer{}
+	defer func(logger zerolog.Logger) { log.Logger = logger }(log.Logger)
+	log.Logger = zerolog.New(buf)
+
+	ctx, span := Tracer().Start(context.Background(), "test")
+	Logger(ctx).Info().Msg("traced")
+	span.End()
+
+	line := make(map[string]string)
+	err := json.Unmarshal(buf.Bytes(), &line)
+	if err != nil {
+		t.Fatalf("failed to unmarshal log line: %v", err)
+	}
+
+	if line["trace_id"] != span.SpanContext().TraceID().String() || line["span_id"] != span.SpanContext().SpanID().String() {
+		t.Fatalf("expected the ids of the span in the log line, got %v", line)
+	}
+
+	buf.Reset()
+	Logger(context.Background()).Info().Msg("untraced")
+	if bytes.Contains(buf.Bytes(), []byte("trace_id")) {
+		t.Fatalf("expected no trace id without a span, got %s", buf.String())
+	}
+}